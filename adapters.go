@@ -0,0 +1,219 @@
+package maps
+
+import (
+	"sync"
+)
+
+// GoMap adapts a builtin map to the Map interface. Since GoMap is a map type, a map
+// is adapted by a conversion that doesn't copy the map:
+//
+//	m := map[string]int{"a": 1}
+//	keys := KeysOf[string, int](GoMap[string, int](m))
+//
+// The functions operating on a Map use the builtin map directly when all maps they
+// operate on are GoMaps. Like any builtin map, setting a key of a nil GoMap panics.
+type GoMap[K comparable, V any] map[K]V
+
+// AsMap adapts a builtin map, including named map types, to the Map interface.
+func AsMap[M ~map[K]V, K comparable, V any](m M) GoMap[K, V] {
+	return GoMap[K, V](m)
+}
+
+// Get returns the value of the key and whether it exists.
+func (m GoMap[K, V]) Get(key K) (V, bool) {
+	val, ok := m[key]
+	return val, ok
+}
+
+// Set sets the value of the key.
+func (m GoMap[K, V]) Set(key K, val V) {
+	m[key] = val
+}
+
+// Delete removes the key, returning true if it existed.
+func (m GoMap[K, V]) Delete(key K) bool {
+	if _, ok := m[key]; !ok {
+		return false
+	}
+	delete(m, key)
+	return true
+}
+
+// Len returns the number of entries.
+func (m GoMap[K, V]) Len() int {
+	return len(m)
+}
+
+// All returns an iterator over the entries of the map.
+//
+// The entries will be visited in an indeterminate order.
+func (m GoMap[K, V]) All() func(yield func(key K, val V) bool) {
+	return func(yield func(key K, val V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// goMap is implemented by GoMap. Since the functions operating on a Map can't
+// assert a Map to a GoMap without K being comparable, GoMap provides the fast paths
+// of those functions as methods. The methods taking another Map return false if it
+// isn't a GoMap of the same type.
+type goMap[K, V any] interface {
+	Map[K, V]
+	keys() []K
+	values() []V
+	entries() []Entry[K, V]
+	filterInto(dst Map[K, V], fn Predicate[K, V]) bool
+	mergeFrom(src Map[K, V], fn ConflictResolver[V]) bool
+	equal(other Map[K, V], eq func(a, b V) bool) (bool, bool)
+	diff(right Map[K, V], eq func(a, b V) bool, add func(key K, left, right V, reason DiffReason)) bool
+}
+
+func (m GoMap[K, V]) keys() []K {
+	return Keys(m)
+}
+
+func (m GoMap[K, V]) values() []V {
+	return Values(m)
+}
+
+func (m GoMap[K, V]) entries() []Entry[K, V] {
+	return Entries(m)
+}
+
+func (m GoMap[K, V]) filterInto(dst Map[K, V], fn Predicate[K, V]) bool {
+	d, ok := dst.(GoMap[K, V])
+	if !ok {
+		return false
+	}
+	for k, v := range m {
+		if fn(k, v) {
+			d[k] = v
+		}
+	}
+	return true
+}
+
+func (m GoMap[K, V]) mergeFrom(src Map[K, V], fn ConflictResolver[V]) bool {
+	s, ok := src.(GoMap[K, V])
+	if !ok {
+		return false
+	}
+	for k, v := range s {
+		if existing, ok := m[k]; ok {
+			v = fn(existing, v)
+		}
+		m[k] = v
+	}
+	return true
+}
+
+func (m GoMap[K, V]) equal(other Map[K, V], eq func(a, b V) bool) (bool, bool) {
+	o, ok := other.(GoMap[K, V])
+	if !ok {
+		return false, false
+	}
+	if len(m) != len(o) {
+		return false, true
+	}
+	for k, v1 := range m {
+		if v2, ok := o[k]; !ok || !eq(v1, v2) {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func (m GoMap[K, V]) diff(right Map[K, V], eq func(a, b V) bool, add func(key K, left, right V, reason DiffReason)) bool {
+	r, ok := right.(GoMap[K, V])
+	if !ok {
+		return false
+	}
+	var zero V
+	for k, v := range m {
+		if other, ok := r[k]; !ok {
+			add(k, v, zero, DiffMissingRight)
+		} else if !eq(v, other) {
+			add(k, v, other, DiffValue)
+		}
+	}
+	for k, v := range r {
+		if _, ok := m[k]; !ok {
+			add(k, zero, v, DiffMissingLeft)
+		}
+	}
+	return true
+}
+
+// SyncMap adapts a sync.Map holding keys of type K and values of type V to the Map
+// interface. Like sync.Map, SyncMap is safe for concurrent use. Since sync.Map
+// doesn't track its size, Len visits every entry.
+type SyncMap[K comparable, V any] struct {
+	m *sync.Map
+}
+
+// NewSyncMap creates a SyncMap backed by a new sync.Map.
+func NewSyncMap[K comparable, V any]() *SyncMap[K, V] {
+	return &SyncMap[K, V]{m: new(sync.Map)}
+}
+
+// AsSyncMap adapts an existing sync.Map. All keys of the sync.Map must be of type K
+// and all values of type V.
+func AsSyncMap[K comparable, V any](m *sync.Map) *SyncMap[K, V] {
+	return &SyncMap[K, V]{m: m}
+}
+
+// Get returns the value of the key and whether it exists.
+func (m *SyncMap[K, V]) Get(key K) (V, bool) {
+	val, ok := m.m.Load(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	v, _ := val.(V)
+	return v, true
+}
+
+// Set sets the value of the key.
+func (m *SyncMap[K, V]) Set(key K, val V) {
+	m.m.Store(key, val)
+}
+
+// Delete removes the key, returning true if it existed.
+func (m *SyncMap[K, V]) Delete(key K) bool {
+	_, loaded := m.m.LoadAndDelete(key)
+	return loaded
+}
+
+// Len returns the number of entries.
+func (m *SyncMap[K, V]) Len() int {
+	n := 0
+	m.m.Range(func(key, val any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// All returns an iterator over the entries of the map. Unlike the other Map
+// implementations, entries may be added or removed while iterating with the same
+// guarantees as sync.Map.Range.
+//
+// The entries will be visited in an indeterminate order.
+func (m *SyncMap[K, V]) All() func(yield func(key K, val V) bool) {
+	return func(yield func(key K, val V) bool) {
+		m.m.Range(func(key, val any) bool {
+			k, _ := key.(K)
+			v, _ := val.(V)
+			return yield(k, v)
+		})
+	}
+}
+
+// Unwrap returns the underlying sync.Map.
+func (m *SyncMap[K, V]) Unwrap() *sync.Map {
+	return m.m
+}
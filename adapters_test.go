@@ -0,0 +1,172 @@
+package maps
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ Map[string, int]    = GoMap[string, int]{}
+	_ Map[string, int]    = (*SyncMap[string, int])(nil)
+	_ Map[string, int]    = (*NormalizedMap[int])(nil)
+	_ Map[[]byte, string] = (*HashMap[[]byte, string])(nil)
+)
+
+type namedMap map[string]int
+
+func TestGoMap(t *testing.T) {
+	raw := namedMap{"a": 1}
+	m := AsMap(raw)
+	_, fast := Map[string, int](m).(goMap[string, int])
+	assert.True(t, fast, "GoMap must provide the fast paths")
+	m.Set("b", 2)
+	assert.Equal(t, namedMap{"a": 1, "b": 2}, raw, "the adapter must not copy the map")
+
+	val, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	assert.Equal(t, 2, m.Len())
+	assert.True(t, m.Delete("a"))
+	assert.False(t, m.Delete("a"))
+
+	visited := 0
+	m.All()(func(key string, val int) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 1, visited)
+}
+
+func TestSyncMap(t *testing.T) {
+	m := NewSyncMap[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	val, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	_, ok = m.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+	assert.True(t, m.Delete("a"))
+	assert.False(t, m.Delete("a"))
+	assert.Equal(t, 1, m.Len())
+
+	var raw sync.Map
+	raw.Store("x", 10)
+	adapted := AsSyncMap[string, int](&raw)
+	adapted.Set("y", 20)
+	assert.Same(t, &raw, adapted.Unwrap())
+	assert.Equal(t, map[string]int{"x": 10, "y": 20}, collectMap[string, int](adapted))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.Set(string(rune('c'+i)), i)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 9, m.Len())
+}
+
+func collectMap[K comparable, V any](m Map[K, V]) map[K]V {
+	res := make(map[K]V)
+	m.All()(func(key K, val V) bool {
+		res[key] = val
+		return true
+	})
+	return res
+}
+
+// testMaps returns the entries as each Map implementation with comparable keys, so
+// the functions operating on a Map are tested on both their fast and generic paths.
+func testMaps(entries map[string]int) map[string]func() Map[string, int] {
+	return map[string]func() Map[string, int]{
+		"GoMap": func() Map[string, int] {
+			return AsMap(Clone(entries))
+		},
+		"SyncMap": func() Map[string, int] {
+			m := NewSyncMap[string, int]()
+			for k, v := range entries {
+				m.Set(k, v)
+			}
+			return m
+		},
+		"HashMap": func() Map[string, int] {
+			m := NewHashMap[string, int](ProjectionHasher(func(key string) string { return key }))
+			for k, v := range entries {
+				m.Set(k, v)
+			}
+			return m
+		},
+	}
+}
+
+func TestMapFunctions_AcrossImplementations(t *testing.T) {
+	left := map[string]int{"a": 1, "b": 2, "c": 3}
+	right := map[string]int{"b": 2, "c": 4, "d": 5}
+
+	for leftName, newLeft := range testMaps(left) {
+		for rightName, newRight := range testMaps(right) {
+			t.Run(leftName+" And "+rightName, func(t *testing.T) {
+				keys := KeysOf(newLeft())
+				sort.Strings(keys)
+				assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+				vals := ValuesOf(newLeft())
+				sort.Ints(vals)
+				assert.Equal(t, []int{1, 2, 3}, vals)
+				assert.ElementsMatch(t, Entries(left), EntriesOf(newLeft()))
+
+				filtered := newRight()
+				FilterInto(filtered, newLeft(), func(key string, val int) bool {
+					return val > 1
+				})
+				assert.Equal(t, map[string]int{"b": 2, "c": 3, "d": 5}, collectMap(filtered))
+
+				merged := newRight()
+				MergeInto(merged, SumResolver[int](), newLeft(), newLeft())
+				assert.Equal(t, map[string]int{"a": 2, "b": 6, "c": 10, "d": 5}, collectMap(merged))
+
+				diff := make(map[string]EntryComparison[int])
+				for _, entry := range DiffOf(newLeft(), newRight()) {
+					diff[entry.Key] = entry.EntryComparison
+				}
+				assert.Equal(t, Diff(left, right), diff)
+
+				assert.True(t, EqualOf[string, int](newLeft(), AsMap(Clone(left))))
+				assert.True(t, EqualOf[string, int](AsMap(Clone(right)), newRight()))
+				assert.False(t, EqualOf(newLeft(), newRight()))
+			})
+		}
+	}
+}
+
+func TestEqualOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		m1       map[string]int
+		m2       map[string]int
+		expected bool
+	}{
+		{name: "Empty", m1: map[string]int{}, m2: nil, expected: true},
+		{name: "Equal", m1: map[string]int{"a": 1}, m2: map[string]int{"a": 1}, expected: true},
+		{name: "Different Value", m1: map[string]int{"a": 1}, m2: map[string]int{"a": 2}, expected: false},
+		{name: "Different Keys", m1: map[string]int{"a": 1}, m2: map[string]int{"b": 1}, expected: false},
+		{name: "Different Length", m1: map[string]int{"a": 1}, m2: map[string]int{"a": 1, "b": 1}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for name, newMap := range testMaps(test.m2) {
+				assert.Equal(t, test.expected, EqualOf[string, int](AsMap(test.m1), newMap()), name)
+				assert.Equal(t, test.expected, EqualOf[string, int](newMap(), AsMap(test.m1)), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package config layers configuration sources such as files, environment variables
+// and flags into a single nested map using the maps package.
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jkratz55/maps-go"
+)
+
+// Config is the result of loading a Loader.
+type Config struct {
+	// Values is the merged configuration.
+	Values map[string]any
+	// Provenance records the source of every value, keyed by the flattened key of
+	// the value such as "server.port". Slices are not flattened and dots within
+	// keys are escaped with a backslash.
+	Provenance map[string]maps.Provenance
+}
+
+// provenanceKeys are the options used to flatten the keys of Config.Provenance.
+var provenanceKeys = maps.FlattenOptions{Index: maps.IndexNone, Escape: true}
+
+// Loader loads configuration from multiple sources and deep merges them into a
+// single nested map. Sources are merged in order, so later sources take precedence
+// over earlier ones.
+type Loader struct {
+	// Sources are merged in order of increasing precedence, for example defaults,
+	// files, environment variables and flags.
+	Sources []Source
+	// Strategy controls how the sources are merged. NewLoader defaults it to
+	// replace slices and to let values override maps of a lower precedence source
+	// and vice versa.
+	Strategy maps.MergeStrategy
+}
+
+// NewLoader creates a Loader merging the sources in order of increasing precedence.
+func NewLoader(sources ...Source) *Loader {
+	return &Loader{
+		Sources:  sources,
+		Strategy: maps.MergeStrategy{Slices: maps.SliceReplace, TypeMismatch: maps.TypeMismatchOverwrite},
+	}
+}
+
+// Load loads every source and merges them. Loading fails if any source fails.
+func (l *Loader) Load() (*Config, error) {
+	loaded := make([]map[string]any, len(l.Sources))
+	named := make([]maps.NamedMap[string, any], len(l.Sources))
+	for i, src := range l.Sources {
+		m, err := src.Load()
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", src.Name(), err)
+		}
+		flat, err := maps.Flatten(m, provenanceKeys)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", src.Name(), err)
+		}
+		loaded[i] = m
+		named[i] = maps.NamedMap[string, any]{Name: src.Name(), Map: flat}
+	}
+
+	values, err := maps.DeepMerge(l.Strategy, loaded...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Provenance is tracked on the flattened sources. Keys that don't survive the
+	// merge, such as the nested keys of a map overridden by a value, are dropped.
+	flat, err := maps.Flatten(values, provenanceKeys)
+	if err != nil {
+		return nil, err
+	}
+	_, provenance := maps.MergeTracked(maps.OverwriteResolver[any](), named...)
+	for k := range provenance {
+		if _, ok := flat[k]; !ok {
+			delete(provenance, k)
+		}
+	}
+
+	return &Config{Values: values, Provenance: provenance}, nil
+}
+
+// Update is sent by Loader.Watch when the configuration changed or failed to load.
+type Update struct {
+	// Config is the newly loaded configuration, nil if Err is set.
+	Config *Config
+	// Changes lists the differences from the previous configuration.
+	Changes []maps.Change
+	// Err is the error that occurred while reloading. The previous configuration
+	// remains in effect.
+	Err error
+}
+
+// Watch loads the configuration and then polls the files of the File and
+// OptionalFile sources every interval. When a file was modified, created or
+// removed the configuration is reloaded and, if it changed, an Update holding the
+// DeepDiff between the previous and the new configuration is sent on the returned
+// channel. Failed reloads are sent as an Update with Err set.
+//
+// The channel is closed once the context is done. The initial configuration is
+// returned, or an error if it can't be loaded.
+func (l *Loader) Watch(ctx context.Context, interval time.Duration) (*Config, <-chan Update, error) {
+	var files []string
+	for _, src := range l.Sources {
+		if f, ok := src.(*fileSource); ok {
+			files = append(files, f.path)
+		}
+	}
+
+	// The files are stated before loading so modifications made while loading are
+	// picked up by the first poll.
+	states := fileStates(files)
+	current, err := l.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updates := make(chan Update)
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			next := fileStates(files)
+			if statesEqual(states, next) {
+				continue
+			}
+			states = next
+
+			var update Update
+			cfg, err := l.Load()
+			if err != nil {
+				update.Err = err
+			} else {
+				update.Changes = maps.DeepDiff(current.Values, cfg.Values)
+				if len(update.Changes) == 0 {
+					continue
+				}
+				update.Config = cfg
+				current = cfg
+			}
+
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return current, updates, nil
+}
+
+// fileState identifies a version of a file, the zero value represents a file that
+// doesn't exist.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func fileStates(files []string) []fileState {
+	states := make([]fileState, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			states[i] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return states
+}
+
+func statesEqual(a, b []fileState) bool {
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jkratz55/maps-go"
+)
+
+func TestLoader_Load(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "config.yaml", "server:\n  host: 0.0.0.0\n  port: 9090\nlog:\n  level: info\ntags: [a, b]\n")
+	t.Setenv("APP_SERVER__PORT", "10000")
+	t.Setenv("APP_LOG", "stdout")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("debug", false, "")
+	assert.NoError(t, fs.Parse([]string{"-debug"}))
+
+	loader := NewLoader(
+		Map("defaults", map[string]any{
+			"server": map[string]any{"host": "localhost", "port": 8080, "timeout": "30s"},
+			"tags":   []any{"default"},
+		}),
+		File(file),
+		Env("APP_", "__"),
+		Flags(fs),
+		Map("overrides", map[string]any{"server": map[string]any{"host": "127.0.0.1"}}),
+	)
+
+	cfg, err := loader.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"host": "127.0.0.1", "port": "10000", "timeout": "30s"},
+		"log":    "stdout",
+		"tags":   []any{"a", "b"},
+		"debug":  true,
+	}, cfg.Values)
+
+	assert.Equal(t, map[string]maps.Provenance{
+		"server.host":    {Source: "overrides", Overridden: []string{"defaults", file}},
+		"server.port":    {Source: "env", Overridden: []string{"defaults", file}},
+		"server.timeout": {Source: "defaults"},
+		"log":            {Source: "env"},
+		"tags":           {Source: file, Overridden: []string{"defaults"}},
+		"debug":          {Source: "flags"},
+	}, cfg.Provenance)
+
+	port, err := maps.GetInt(cfg.Values["server"].(map[string]any), "port")
+	assert.NoError(t, err)
+	assert.Equal(t, 10000, port)
+}
+
+func TestLoader_LoadError(t *testing.T) {
+	loader := NewLoader(Map("defaults", map[string]any{}), File("missing.json"))
+	_, err := loader.Load()
+	assert.ErrorContains(t, err, "load missing.json: ")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	loader = &Loader{Sources: []Source{
+		Map("a", map[string]any{"server": map[string]any{"port": 1}}),
+		Map("b", map[string]any{"server": "localhost"}),
+	}}
+	_, err = loader.Load()
+	assert.IsType(t, &maps.MergeTypeError{}, err)
+}
+
+func TestLoader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "config.json", `{"server": {"port": 8080}}`)
+	optional := dir + "/local.json"
+
+	loader := NewLoader(File(file), OptionalFile(optional))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, updates, err := loader.Watch(ctx, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"server": map[string]any{"port": float64(8080)}}, cfg.Values)
+
+	next := func() Update {
+		t.Helper()
+		select {
+		case update := <-updates:
+			return update
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for update")
+			return Update{}
+		}
+	}
+
+	// Modification times may have a coarse resolution, so they are set explicitly.
+	touch := func(path, content string, mod time.Time) {
+		t.Helper()
+		writeFile(t, dir, path[len(dir)+1:], content)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	touch(file, `{"server": {"port": 9090}}`, time.Now().Add(time.Hour))
+	update := next()
+	assert.NoError(t, update.Err)
+	assert.Equal(t, []maps.Change{
+		{Path: "/server/port", Old: float64(8080), New: float64(9090), Reason: maps.DiffModified},
+	}, update.Changes)
+	assert.Equal(t, map[string]any{"server": map[string]any{"port": float64(9090)}}, update.Config.Values)
+
+	touch(optional, `{"debug": true}`, time.Now().Add(2*time.Hour))
+	update = next()
+	assert.Equal(t, []maps.Change{
+		{Path: "/debug", New: true, Reason: maps.DiffAdded},
+	}, update.Changes)
+
+	touch(file, `{"server": `, time.Now().Add(3*time.Hour))
+	update = next()
+	assert.Error(t, update.Err)
+	assert.Nil(t, update.Config)
+
+	cancel()
+	for range updates {
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jkratz55/maps-go"
+)
+
+// Source provides a layer of configuration as a nested map.
+type Source interface {
+	// Name identifies the source in provenance and error messages.
+	Name() string
+	// Load reads the configuration. Load is called every time the Loader loads and
+	// must return a map the caller may modify.
+	Load() (map[string]any, error)
+}
+
+type fileSource struct {
+	path     string
+	optional bool
+}
+
+// File returns a Source reading a JSON or YAML file, determined by its extension
+// ".json", ".yaml" or ".yml". Loading fails if the file doesn't exist. File sources
+// are watched for changes by Loader.Watch.
+func File(path string) Source {
+	return &fileSource{path: path}
+}
+
+// OptionalFile returns a Source like File that provides no configuration if the
+// file doesn't exist.
+func OptionalFile(path string) Source {
+	return &fileSource{path: path, optional: true}
+}
+
+func (s *fileSource) Name() string {
+	return s.path
+}
+
+func (s *fileSource) Load() (map[string]any, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if s.optional && errors.Is(err, fs.ErrNotExist) {
+			return map[string]any{}, nil
+		}
+		return nil, err
+	}
+
+	m := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(s.path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &m)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &m)
+	default:
+		return nil, fmt.Errorf("unsupported file extension %q", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		// An empty YAML document decodes to a nil map.
+		m = make(map[string]any)
+	}
+	return m, nil
+}
+
+type envSource struct {
+	prefix    string
+	separator string
+}
+
+// Env returns a Source reading the environment variables starting with prefix. The
+// prefix is removed, the remainder lower cased and split on separator into nested
+// keys, so with the prefix "APP_" and separator "__" the variable APP_SERVER__PORT
+// sets "server.port" and APP_LOG_LEVEL sets "log_level".
+//
+// Values are strings, use the weakly typed getters of the maps package or
+// maps.StructOptions.WeaklyTyped to convert them.
+func Env(prefix string, separator string) Source {
+	return &envSource{prefix: prefix, separator: separator}
+}
+
+func (s *envSource) Name() string {
+	return "env"
+}
+
+func (s *envSource) Load() (map[string]any, error) {
+	flat := make(map[string]any)
+	for _, kv := range os.Environ() {
+		key, val, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, s.prefix) || key == s.prefix {
+			continue
+		}
+		flat[strings.ToLower(strings.TrimPrefix(key, s.prefix))] = val
+	}
+	return maps.Unflatten(flat, maps.FlattenOptions{Separator: s.separator, Index: maps.IndexNone})
+}
+
+type flagSource struct {
+	flags *flag.FlagSet
+}
+
+// Flags returns a Source reading the flags of the FlagSet that were set on the
+// command line, so flag defaults don't override other sources. Flag names are split
+// on "." into nested keys, so the flag -server.port sets "server.port". Values are
+// typed if the flag implements flag.Getter, as all flags of the flag package do,
+// and strings otherwise. The FlagSet must be parsed before loading.
+func Flags(flags *flag.FlagSet) Source {
+	return &flagSource{flags: flags}
+}
+
+func (s *flagSource) Name() string {
+	return "flags"
+}
+
+func (s *flagSource) Load() (map[string]any, error) {
+	flat := make(map[string]any)
+	s.flags.Visit(func(f *flag.Flag) {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			flat[f.Name] = getter.Get()
+		} else {
+			flat[f.Name] = f.Value.String()
+		}
+	})
+	return maps.Unflatten(flat, maps.FlattenOptions{Index: maps.IndexNone})
+}
+
+type mapSource struct {
+	name string
+	m    map[string]any
+}
+
+// Map returns a Source providing a nested map, such as defaults or explicit
+// overrides set by the application. The map is copied on every load.
+func Map(name string, m map[string]any) Source {
+	return &mapSource{name: name, m: m}
+}
+
+func (s *mapSource) Name() string {
+	return s.name
+}
+
+func (s *mapSource) Load() (map[string]any, error) {
+	return maps.DeepMerge(maps.MergeStrategy{}, s.m)
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected map[string]any
+	}{
+		{
+			name:     "JSON",
+			file:     "config.json",
+			content:  `{"server": {"port": 8080, "hosts": ["a"]}}`,
+			expected: map[string]any{"server": map[string]any{"port": float64(8080), "hosts": []any{"a"}}},
+		},
+		{
+			name:     "YAML",
+			file:     "config.yaml",
+			content:  "server:\n  port: 8080\n  hosts: [a]\n",
+			expected: map[string]any{"server": map[string]any{"port": 8080, "hosts": []any{"a"}}},
+		},
+		{
+			name:     "Empty YAML",
+			file:     "empty.yml",
+			content:  "",
+			expected: map[string]any{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := File(writeFile(t, dir, test.file, test.content))
+			m, err := src.Load()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, m)
+		})
+	}
+
+	_, err := File(writeFile(t, dir, "config.toml", "")).Load()
+	assert.EqualError(t, err, `unsupported file extension ".toml"`)
+
+	_, err = File(filepath.Join(dir, "missing.json")).Load()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	m, err := OptionalFile(filepath.Join(dir, "missing.json")).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{}, m)
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("APP_SERVER__PORT", "9090")
+	t.Setenv("APP_SERVER__TLS__CERT", "cert.pem")
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("OTHER_SERVER__PORT", "1")
+
+	src := Env("APP_", "__")
+	assert.Equal(t, "env", src.Name())
+
+	m, err := src.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{
+			"port": "9090",
+			"tls":  map[string]any{"cert": "cert.pem"},
+		},
+		"log_level": "debug",
+	}, m)
+
+	t.Setenv("APP_SERVER", "conflict")
+	_, err = src.Load()
+	assert.EqualError(t, err, `key "server__port" conflicts with key "server"`)
+}
+
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("server.port", 8080, "")
+	fs.Bool("debug", false, "")
+	fs.String("server.host", "localhost", "")
+	assert.NoError(t, fs.Parse([]string{"-server.port", "9090", "-debug"}))
+
+	m, err := Flags(fs).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"port": 9090},
+		"debug":  true,
+	}, m)
+}
+
+func TestMap(t *testing.T) {
+	defaults := map[string]any{"server": map[string]any{"port": 8080}}
+	src := Map("defaults", defaults)
+	assert.Equal(t, "defaults", src.Name())
+
+	m, err := src.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, defaults, m)
+
+	m["server"].(map[string]any)["port"] = 1
+	assert.Equal(t, 8080, defaults["server"].(map[string]any)["port"])
+}
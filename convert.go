@@ -0,0 +1,281 @@
+package maps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errIncompatibleType = errors.New("incompatible type")
+	errOutOfRange       = errors.New("value out of range")
+	errNotInteger       = errors.New("not an integer")
+
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// ConversionError is returned when a value can't be converted to the requested
+// type. Key identifies the value, for nested values decoded by ToStruct it is the
+// JSON Pointer (RFC 6901) of the value. Err holds the cause, if any, such as a
+// parsing error.
+type ConversionError struct {
+	Key   string
+	Value any
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *ConversionError) Error() string {
+	val := fmt.Sprintf("%v", e.Value)
+	if s, ok := e.Value.(string); ok {
+		val = strconv.Quote(s)
+	}
+	msg := fmt.Sprintf("key %q: cannot convert %s (%T) to %v", e.Key, val, e.Value, e.Type)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// newConversionError returns a *ConversionError for the key, omitting the cause if
+// the types are simply incompatible.
+func newConversionError(key string, val any, t reflect.Type, err error) *ConversionError {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	if errors.Is(err, errIncompatibleType) {
+		err = nil
+	}
+	return &ConversionError{Key: key, Value: val, Type: t, Err: err}
+}
+
+// convertScalar converts a value to a type whose kind is a number, bool or string,
+// or to time.Duration. Numbers are converted between kinds as long as the value is
+// preserved, so the float64 80 decoded from JSON converts to int but 1.5 doesn't.
+// If weak is true strings are also parsed, and numbers and bools formatted, to
+// satisfy the target type.
+func convertScalar(in any, t reflect.Type, weak bool) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	if t == durationType {
+		d, err := toDuration(in, weak)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetInt(int64(d))
+		return out, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(in, weak)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if out.OverflowInt(i) {
+			return reflect.Value{}, errOutOfRange
+		}
+		out.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := toUint64(in, weak)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if out.OverflowUint(u) {
+			return reflect.Value{}, errOutOfRange
+		}
+		out.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(in, weak)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if out.OverflowFloat(f) {
+			return reflect.Value{}, errOutOfRange
+		}
+		out.SetFloat(f)
+	case reflect.Bool:
+		b, err := toBool(in, weak)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetBool(b)
+	case reflect.String:
+		s, err := toString(in, weak)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetString(s)
+	default:
+		return reflect.Value{}, errIncompatibleType
+	}
+	return out, nil
+}
+
+func toInt64(in any, weak bool) (int64, error) {
+	v := reflect.ValueOf(in)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, errOutOfRange
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return floatToInt64(v.Float())
+	}
+
+	if n, ok := in.(json.Number); ok {
+		return parseInt64(string(n))
+	}
+	if weak {
+		switch val := in.(type) {
+		case string:
+			return parseInt64(strings.TrimSpace(val))
+		case bool:
+			if val {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, errIncompatibleType
+}
+
+// parseInt64 parses an integer, accepting base prefixes and floating point notation
+// of integral values such as "1e3".
+func parseInt64(s string) (int64, error) {
+	i, err := strconv.ParseInt(s, 0, 64)
+	if err == nil {
+		return i, nil
+	}
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil {
+		return 0, err
+	}
+	return floatToInt64(f)
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, errNotInteger
+	}
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, errOutOfRange
+	}
+	return int64(f), nil
+}
+
+func toUint64(in any, weak bool) (uint64, error) {
+	v := reflect.ValueOf(in)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.String:
+		if _, ok := in.(json.Number); !ok && !weak {
+			return 0, errIncompatibleType
+		}
+		if u, err := strconv.ParseUint(strings.TrimSpace(v.String()), 0, 64); err == nil {
+			return u, nil
+		}
+	}
+
+	i, err := toInt64(in, weak)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, errOutOfRange
+	}
+	return uint64(i), nil
+}
+
+func toFloat64(in any, weak bool) (float64, error) {
+	v := reflect.ValueOf(in)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+
+	if n, ok := in.(json.Number); ok {
+		return strconv.ParseFloat(string(n), 64)
+	}
+	if weak {
+		switch val := in.(type) {
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(val), 64)
+		case bool:
+			if val {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, errIncompatibleType
+}
+
+func toBool(in any, weak bool) (bool, error) {
+	v := reflect.ValueOf(in)
+	if v.Kind() == reflect.Bool {
+		return v.Bool(), nil
+	}
+	if !weak {
+		return false, errIncompatibleType
+	}
+	if s, ok := in.(string); ok {
+		return strconv.ParseBool(strings.TrimSpace(s))
+	}
+	if f, err := toFloat64(in, false); err == nil {
+		return f != 0, nil
+	}
+	return false, errIncompatibleType
+}
+
+func toString(in any, weak bool) (string, error) {
+	v := reflect.ValueOf(in)
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if !weak {
+		return "", errIncompatibleType
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Slice:
+		if b, ok := in.([]byte); ok {
+			return string(b), nil
+		}
+	}
+	return "", errIncompatibleType
+}
+
+// toDuration converts a time.Duration or an integer number of nanoseconds, and if
+// weak is true a duration string such as "1m30s", to a time.Duration.
+func toDuration(in any, weak bool) (time.Duration, error) {
+	if s, ok := in.(string); ok && weak {
+		return time.ParseDuration(strings.TrimSpace(s))
+	}
+	i, err := toInt64(in, false)
+	return time.Duration(i), err
+}
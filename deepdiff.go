@@ -0,0 +1,263 @@
+package maps
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change describes a single difference between two nested documents found by
+// DeepDiff.
+type Change struct {
+	// Path is the JSON Pointer (RFC 6901) of the value that changed.
+	Path string
+	// Old is the value in the left document, or nil if it was added.
+	Old any
+	// New is the value in the right document, or nil if it was removed.
+	New any
+	// Reason is one of DiffAdded, DiffRemoved, DiffModified or DiffTypeChanged.
+	Reason DiffReason
+}
+
+// DeepDiffOption configures the behavior of DeepDiff.
+type DeepDiffOption func(opts *deepDiffOptions)
+
+type deepDiffOptions struct {
+	ignore           [][]string
+	nilEqualsMissing bool
+	sliceKeys        []sliceKey
+}
+
+type sliceKey struct {
+	field    string
+	patterns [][]string
+}
+
+// IgnorePaths excludes the values at the given JSON Pointers, and everything nested
+// beneath them, from the comparison. A "*" token in a path matches any single
+// key or index, for example "/containers/*/image".
+func IgnorePaths(paths ...string) DeepDiffOption {
+	return func(opts *deepDiffOptions) {
+		for _, path := range paths {
+			opts.ignore = append(opts.ignore, splitPointer(path))
+		}
+	}
+}
+
+// NilEqualsMissing treats a key holding a nil value as equal to the key not being
+// present at all.
+func NilEqualsMissing() DeepDiffOption {
+	return func(opts *deepDiffOptions) {
+		opts.nilEqualsMissing = true
+	}
+}
+
+// MatchSlicesByKey matches the elements of slices by the value of the given field
+// instead of by their index. Only slices whose elements are all maps holding a
+// unique value for the field are matched by key, other slices fall back to being
+// compared by index. If paths are provided only the slices at those JSON Pointers
+// are matched by key, a "*" token in a path matches any single key or index.
+//
+// Changes within slices matched by key are reported with the value of the field in
+// place of the element's index, for example "/containers/app/image" for the image of
+// the element whose name is "app". The same paths are matched by IgnorePaths.
+func MatchSlicesByKey(field string, paths ...string) DeepDiffOption {
+	return func(opts *deepDiffOptions) {
+		key := sliceKey{field: field}
+		for _, path := range paths {
+			key.patterns = append(key.patterns, splitPointer(path))
+		}
+		opts.sliceKeys = append(opts.sliceKeys, key)
+	}
+}
+
+// DeepDiff recursively compares two nested documents, such as decoded JSON or YAML,
+// and returns a flat list of every change between them. Nested maps and slices are
+// walked while all other values are compared with reflect.DeepEqual.
+//
+// The changes are ordered by path with map keys visited in sorted order.
+func DeepDiff(left, right map[string]any, opts ...DeepDiffOption) []Change {
+	differ := deepDiffer{}
+	for _, opt := range opts {
+		opt(&differ.opts)
+	}
+	differ.diffMaps("", nil, left, right)
+	return differ.changes
+}
+
+type deepDiffer struct {
+	opts    deepDiffOptions
+	changes []Change
+}
+
+func (d *deepDiffer) ignored(tokens []string) bool {
+	for _, pattern := range d.opts.ignore {
+		if matchPointer(pattern, tokens, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *deepDiffer) add(path string, old, new any, reason DiffReason) {
+	d.changes = append(d.changes, Change{
+		Path:   path,
+		Old:    old,
+		New:    new,
+		Reason: reason,
+	})
+}
+
+func (d *deepDiffer) diffValues(path string, tokens []string, left, right any) {
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			d.diffMaps(path, tokens, l, r)
+			return
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			d.diffSlices(path, tokens, l, r)
+			return
+		}
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return
+	}
+	reason := DiffModified
+	if left != nil && right != nil && reflect.TypeOf(left) != reflect.TypeOf(right) {
+		reason = DiffTypeChanged
+	}
+	d.add(path, left, right, reason)
+}
+
+func (d *deepDiffer) diffMaps(path string, tokens []string, left, right map[string]any) {
+	keys := make([]string, 0, len(left)+len(right))
+	for k := range left {
+		keys = append(keys, k)
+	}
+	for k := range right {
+		if _, ok := left[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := joinPointer(path, key)
+		childTokens := append(tokens[:len(tokens):len(tokens)], key)
+		if d.ignored(childTokens) {
+			continue
+		}
+
+		lv, lok := left[key]
+		rv, rok := right[key]
+		switch {
+		case lok && rok:
+			d.diffValues(childPath, childTokens, lv, rv)
+		case lok:
+			if lv == nil && d.opts.nilEqualsMissing {
+				continue
+			}
+			d.add(childPath, lv, nil, DiffRemoved)
+		default:
+			if rv == nil && d.opts.nilEqualsMissing {
+				continue
+			}
+			d.add(childPath, nil, rv, DiffAdded)
+		}
+	}
+}
+
+func (d *deepDiffer) diffSlices(path string, tokens []string, left, right []any) {
+	if field, ok := d.sliceKeyFor(tokens, left, right); ok {
+		d.diffKeyedSlices(path, tokens, field, left, right)
+		return
+	}
+
+	n := len(left)
+	if len(right) > n {
+		n = len(right)
+	}
+	for i := 0; i < n; i++ {
+		index := strconv.Itoa(i)
+		childPath := joinPointer(path, index)
+		childTokens := append(tokens[:len(tokens):len(tokens)], index)
+		if d.ignored(childTokens) {
+			continue
+		}
+
+		switch {
+		case i < len(left) && i < len(right):
+			d.diffValues(childPath, childTokens, left[i], right[i])
+		case i < len(left):
+			d.add(childPath, left[i], nil, DiffRemoved)
+		default:
+			d.add(childPath, nil, right[i], DiffAdded)
+		}
+	}
+}
+
+// diffKeyedSlices compares slices whose elements are matched by the value of field.
+// The elements are compared like map entries keyed by their key segment, so every
+// change is reported under the key of the element it belongs to.
+func (d *deepDiffer) diffKeyedSlices(path string, tokens []string, field string, left, right []any) {
+	d.diffMaps(path, tokens, keyedElements(field, left), keyedElements(field, right))
+}
+
+func keyedElements(field string, elems []any) map[string]any {
+	res := make(map[string]any, len(elems))
+	for _, elem := range elems {
+		res[keySegment(elem.(map[string]any)[field])] = elem
+	}
+	return res
+}
+
+// keySegment formats the key of a slice element as the path segment it is reported
+// under.
+func keySegment(key any) string {
+	return fmt.Sprint(key)
+}
+
+// sliceKeyFor returns the field the slices at the given path should be matched by,
+// if any.
+func (d *deepDiffer) sliceKeyFor(tokens []string, left, right []any) (string, bool) {
+	for _, key := range d.opts.sliceKeys {
+		if len(key.patterns) > 0 {
+			matched := false
+			for _, pattern := range key.patterns {
+				if matchPointer(pattern, tokens, false) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if keyedBy(key.field, left) && keyedBy(key.field, right) {
+			return key.field, true
+		}
+	}
+	return "", false
+}
+
+// keyedBy reports whether every element of the slice is a map holding a comparable
+// value for field, with every value formatting to a unique key segment.
+func keyedBy(field string, elems []any) bool {
+	seen := make(map[string]struct{}, len(elems))
+	for _, elem := range elems {
+		key, ok := sliceElementKey(elem, field)
+		if !ok {
+			return false
+		}
+		segment := keySegment(key)
+		if _, dup := seen[segment]; dup {
+			return false
+		}
+		seen[segment] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     map[string]any
+		right    map[string]any
+		opts     []DeepDiffOption
+		expected []Change
+	}{
+		{
+			name: "Equal Documents",
+			left: map[string]any{
+				"server": map[string]any{"port": 8080},
+				"tags":   []any{"a", "b"},
+			},
+			right: map[string]any{
+				"server": map[string]any{"port": 8080},
+				"tags":   []any{"a", "b"},
+			},
+			expected: nil,
+		},
+		{
+			name: "Added, Removed, Modified and Type Changed",
+			left: map[string]any{
+				"name":    "api",
+				"replica": 2,
+				"debug":   true,
+				"port":    "8080",
+			},
+			right: map[string]any{
+				"name":    "api",
+				"replica": 3,
+				"region":  "us-east-1",
+				"port":    8080,
+			},
+			expected: []Change{
+				{Path: "/debug", Old: true, New: nil, Reason: DiffRemoved},
+				{Path: "/port", Old: "8080", New: 8080, Reason: DiffTypeChanged},
+				{Path: "/region", Old: nil, New: "us-east-1", Reason: DiffAdded},
+				{Path: "/replica", Old: 2, New: 3, Reason: DiffModified},
+			},
+		},
+		{
+			name: "Nested Maps and Slices",
+			left: map[string]any{
+				"server": map[string]any{
+					"tls": map[string]any{"cert": "a.pem"},
+				},
+				"hosts": []any{"a", "b", "c"},
+			},
+			right: map[string]any{
+				"server": map[string]any{
+					"tls": map[string]any{"cert": "b.pem"},
+				},
+				"hosts": []any{"a", "x"},
+			},
+			expected: []Change{
+				{Path: "/hosts/1", Old: "b", New: "x", Reason: DiffModified},
+				{Path: "/hosts/2", Old: "c", New: nil, Reason: DiffRemoved},
+				{Path: "/server/tls/cert", Old: "a.pem", New: "b.pem", Reason: DiffModified},
+			},
+		},
+		{
+			name:  "Map Replaced By Scalar",
+			left:  map[string]any{"server": map[string]any{"port": 1}},
+			right: map[string]any{"server": "localhost"},
+			expected: []Change{
+				{Path: "/server", Old: map[string]any{"port": 1}, New: "localhost", Reason: DiffTypeChanged},
+			},
+		},
+		{
+			name:  "Escapes Path Tokens",
+			left:  map[string]any{"a/b": map[string]any{"c~d": 1}},
+			right: map[string]any{"a/b": map[string]any{"c~d": 2}},
+			expected: []Change{
+				{Path: "/a~1b/c~0d", Old: 1, New: 2, Reason: DiffModified},
+			},
+		},
+		{
+			name: "Ignore Paths",
+			left: map[string]any{
+				"updated": "yesterday",
+				"status":  map[string]any{"observed": 1, "phase": "ok"},
+				"items":   []any{map[string]any{"ts": 1, "v": 1}},
+			},
+			right: map[string]any{
+				"updated": "today",
+				"status":  map[string]any{"observed": 2, "phase": "failed"},
+				"items":   []any{map[string]any{"ts": 2, "v": 1}},
+			},
+			opts: []DeepDiffOption{IgnorePaths("/updated", "/status/observed", "/items/*/ts")},
+			expected: []Change{
+				{Path: "/status/phase", Old: "ok", New: "failed", Reason: DiffModified},
+			},
+		},
+		{
+			name:     "Nil Equals Missing",
+			left:     map[string]any{"a": nil, "b": map[string]any{}},
+			right:    map[string]any{"b": map[string]any{"c": nil}},
+			opts:     []DeepDiffOption{NilEqualsMissing()},
+			expected: nil,
+		},
+		{
+			name:  "Nil Not Equal Missing By Default",
+			left:  map[string]any{"a": nil},
+			right: map[string]any{},
+			expected: []Change{
+				{Path: "/a", Old: nil, New: nil, Reason: DiffRemoved},
+			},
+		},
+		{
+			name: "Match Slices By Key",
+			left: map[string]any{
+				"containers": []any{
+					map[string]any{"name": "app", "image": "app:1"},
+					map[string]any{"name": "sidecar", "image": "proxy:1"},
+					map[string]any{"name": "old", "image": "old:1"},
+				},
+			},
+			right: map[string]any{
+				"containers": []any{
+					map[string]any{"name": "sidecar", "image": "proxy:1"},
+					map[string]any{"name": "app", "image": "app:2"},
+					map[string]any{"name": "new", "image": "new:1"},
+				},
+			},
+			opts: []DeepDiffOption{MatchSlicesByKey("name", "/containers")},
+			expected: []Change{
+				{Path: "/containers/app/image", Old: "app:1", New: "app:2", Reason: DiffModified},
+				{Path: "/containers/new", Old: nil, New: map[string]any{"name": "new", "image": "new:1"}, Reason: DiffAdded},
+				{Path: "/containers/old", Old: map[string]any{"name": "old", "image": "old:1"}, New: nil, Reason: DiffRemoved},
+			},
+		},
+		{
+			name: "Match Slices By Key Ignores Paths By Key",
+			left: map[string]any{
+				"ports": []any{
+					map[string]any{"port": 80, "protocol": "TCP"},
+					map[string]any{"port": 443, "protocol": "TCP"},
+				},
+			},
+			right: map[string]any{
+				"ports": []any{
+					map[string]any{"port": 443, "protocol": "UDP"},
+					map[string]any{"port": 80, "protocol": "UDP"},
+				},
+			},
+			opts: []DeepDiffOption{MatchSlicesByKey("port"), IgnorePaths("/ports/80")},
+			expected: []Change{
+				{Path: "/ports/443/protocol", Old: "TCP", New: "UDP", Reason: DiffModified},
+			},
+		},
+		{
+			name: "Match Slices By Key Falls Back To Index On Ambiguous Keys",
+			left: map[string]any{
+				"items": []any{map[string]any{"id": 1}, map[string]any{"id": "1"}},
+			},
+			right: map[string]any{
+				"items": []any{map[string]any{"id": "1"}, map[string]any{"id": 1}},
+			},
+			opts: []DeepDiffOption{MatchSlicesByKey("id")},
+			expected: []Change{
+				{Path: "/items/0/id", Old: 1, New: "1", Reason: DiffTypeChanged},
+				{Path: "/items/1/id", Old: "1", New: 1, Reason: DiffTypeChanged},
+			},
+		},
+		{
+			name: "Match Slices By Key Falls Back To Index",
+			left: map[string]any{
+				"items": []any{map[string]any{"id": 1}, "scalar"},
+			},
+			right: map[string]any{
+				"items": []any{map[string]any{"id": 2}, "scalar"},
+			},
+			opts: []DeepDiffOption{MatchSlicesByKey("id")},
+			expected: []Change{
+				{Path: "/items/0/id", Old: 1, New: 2, Reason: DiffModified},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := DeepDiff(test.left, test.right, test.opts...)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
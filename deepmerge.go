@@ -0,0 +1,265 @@
+package maps
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SliceStrategy controls how DeepMerge merges two slices found at the same path.
+type SliceStrategy int
+
+const (
+	// SliceReplace replaces the existing slice with the new slice.
+	SliceReplace SliceStrategy = iota
+	// SliceAppend appends the elements of the new slice to the existing slice.
+	SliceAppend
+	// SliceUnion appends the elements of the new slice that are not already present
+	// in the existing slice.
+	SliceUnion
+	// SliceMergeByIndex merges elements at the same index, elements beyond the end of
+	// the existing slice are appended.
+	SliceMergeByIndex
+	// SliceMergeByKey merges map elements holding the same value for the field named
+	// by MergeStrategy.SliceKey. Elements without a matching element are appended.
+	SliceMergeByKey
+)
+
+// TypeMismatchStrategy controls how DeepMerge handles a path holding a map or slice
+// in one source and a value of a different type in another.
+type TypeMismatchStrategy int
+
+const (
+	// TypeMismatchError aborts the merge with a *MergeTypeError.
+	TypeMismatchError TypeMismatchStrategy = iota
+	// TypeMismatchOverwrite replaces the existing value with the new value.
+	TypeMismatchOverwrite
+)
+
+// MergeStrategy controls how DeepMerge merges nested documents. The zero value
+// replaces slices, keeps the last scalar value and fails on type mismatches.
+type MergeStrategy struct {
+	// Slices controls how slices are merged.
+	Slices SliceStrategy
+	// SliceKey is the field used to match elements when Slices is SliceMergeByKey.
+	SliceKey string
+	// Scalars resolves conflicts between values that are neither maps nor slices. If
+	// nil the last value wins, like OverwriteResolver.
+	Scalars ConflictResolver[any]
+	// TypeMismatch controls how a map or slice conflicting with a value of another
+	// type is handled.
+	TypeMismatch TypeMismatchStrategy
+	// Paths overrides the strategy for the values at specific paths. Paths are
+	// either dot separated keys, such as "spec.containers", or JSON Pointers, such
+	// as "/spec/containers". A "*" matches any single key or slice index and exact
+	// paths take precedence over paths containing a "*". An override applies only
+	// to the value at the matching path, values nested beneath it use the top level
+	// strategy again. Paths of an override are ignored.
+	Paths map[string]MergeStrategy
+}
+
+// MergeTypeError is returned by DeepMerge when a map or slice conflicts with a
+// value of a different type and the strategy is TypeMismatchError.
+type MergeTypeError struct {
+	// Path is the JSON Pointer of the conflicting value.
+	Path  string
+	Left  any
+	Right any
+}
+
+func (e *MergeTypeError) Error() string {
+	return fmt.Sprintf("cannot merge %T with %T at %q", e.Right, e.Left, e.Path)
+}
+
+// DeepMerge recursively merges multiple nested documents, such as decoded JSON or
+// YAML, into a single new document. Later sources take precedence over earlier
+// ones. Nested maps are always merged key by key while the strategy controls how
+// slices, scalar values and type mismatches are merged.
+//
+// The sources are not modified and the result doesn't share memory with them.
+func DeepMerge(strategy MergeStrategy, src ...map[string]any) (map[string]any, error) {
+	merger := deepMerger{root: strategy}
+	for path, override := range strategy.Paths {
+		merger.overrides = append(merger.overrides, pathOverride{
+			pattern:  splitPath(path),
+			strategy: override,
+		})
+	}
+
+	// Exact paths take precedence over paths containing wildcards.
+	sort.Slice(merger.overrides, func(i, j int) bool {
+		wi, wj := wildcards(merger.overrides[i].pattern), wildcards(merger.overrides[j].pattern)
+		if wi != wj {
+			return wi < wj
+		}
+		return pointerOf(merger.overrides[i].pattern) < pointerOf(merger.overrides[j].pattern)
+	})
+
+	merged := make(map[string]any)
+	for _, m := range src {
+		if err := merger.mergeMaps(nil, merged, m); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+type pathOverride struct {
+	pattern  []string
+	strategy MergeStrategy
+}
+
+type deepMerger struct {
+	root      MergeStrategy
+	overrides []pathOverride
+}
+
+func (m *deepMerger) strategyFor(tokens []string) MergeStrategy {
+	for _, override := range m.overrides {
+		if matchPointer(override.pattern, tokens, false) {
+			return override.strategy
+		}
+	}
+	return m.root
+}
+
+func wildcards(pattern []string) int {
+	n := 0
+	for _, token := range pattern {
+		if token == "*" {
+			n++
+		}
+	}
+	return n
+}
+
+// mergeMaps merges src into dst in place. dst must not share memory with any of
+// the sources.
+func (m *deepMerger) mergeMaps(tokens []string, dst, src map[string]any) error {
+	keys := Keys(src)
+	sort.Strings(keys)
+	for _, k := range keys {
+		sv := src[k]
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = deepCopyValue(sv)
+			continue
+		}
+		merged, err := m.mergeValues(append(tokens[:len(tokens):len(tokens)], k), dv, sv)
+		if err != nil {
+			return err
+		}
+		dst[k] = merged
+	}
+	return nil
+}
+
+// mergeValues merges right into left, which must not share memory with any of the
+// sources, and returns the merged value.
+func (m *deepMerger) mergeValues(tokens []string, left, right any) (any, error) {
+	strategy := m.strategyFor(tokens)
+
+	leftMap, leftIsMap := left.(map[string]any)
+	rightMap, rightIsMap := right.(map[string]any)
+	leftSlice, leftIsSlice := left.([]any)
+	rightSlice, rightIsSlice := right.([]any)
+
+	switch {
+	case leftIsMap && rightIsMap:
+		if leftMap == nil {
+			return deepCopyValue(rightMap), nil
+		}
+		return leftMap, m.mergeMaps(tokens, leftMap, rightMap)
+	case leftIsSlice && rightIsSlice:
+		return m.mergeSlices(tokens, strategy, leftSlice, rightSlice)
+	case left != nil && right != nil && (leftIsMap || rightIsMap || leftIsSlice || rightIsSlice):
+		if strategy.TypeMismatch == TypeMismatchOverwrite {
+			return deepCopyValue(right), nil
+		}
+		return nil, &MergeTypeError{Path: pointerOf(tokens), Left: left, Right: right}
+	default:
+		if strategy.Scalars == nil {
+			return deepCopyValue(right), nil
+		}
+		return strategy.Scalars(left, deepCopyValue(right)), nil
+	}
+}
+
+func (m *deepMerger) mergeSlices(tokens []string, strategy MergeStrategy, left, right []any) (any, error) {
+	switch strategy.Slices {
+	case SliceAppend:
+		for _, elem := range right {
+			left = append(left, deepCopyValue(elem))
+		}
+		return left, nil
+	case SliceUnion:
+		for _, elem := range right {
+			if !containsValue(left, elem) {
+				left = append(left, deepCopyValue(elem))
+			}
+		}
+		return left, nil
+	case SliceMergeByIndex:
+		for i, elem := range right {
+			if i >= len(left) {
+				left = append(left, deepCopyValue(elem))
+				continue
+			}
+			merged, err := m.mergeValues(append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i)), left[i], elem)
+			if err != nil {
+				return nil, err
+			}
+			left[i] = merged
+		}
+		return left, nil
+	case SliceMergeByKey:
+		index := make(map[any]int, len(left))
+		for i, elem := range left {
+			if key, ok := sliceElementKey(elem, strategy.SliceKey); ok {
+				index[key] = i
+			}
+		}
+		for _, elem := range right {
+			key, ok := sliceElementKey(elem, strategy.SliceKey)
+			if i, found := index[key]; ok && found {
+				merged, err := m.mergeValues(append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i)), left[i], elem)
+				if err != nil {
+					return nil, err
+				}
+				left[i] = merged
+				continue
+			}
+			left = append(left, deepCopyValue(elem))
+			if ok {
+				index[key] = len(left) - 1
+			}
+		}
+		return left, nil
+	default:
+		return deepCopyValue(right), nil
+	}
+}
+
+// sliceElementKey returns the value of field if elem is a map holding a comparable
+// value for it.
+func sliceElementKey(elem any, field string) (any, bool) {
+	m, ok := elem.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	key, ok := m[field]
+	if !ok || key == nil || !reflect.TypeOf(key).Comparable() {
+		return nil, false
+	}
+	return key, true
+}
+
+func containsValue(elems []any, val any) bool {
+	for _, elem := range elems {
+		if jsonEqual(elem, val) {
+			return true
+		}
+	}
+	return false
+}
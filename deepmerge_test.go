@@ -0,0 +1,210 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		src      []map[string]any
+		expected map[string]any
+	}{
+		{
+			name: "Nested Maps",
+			src: []map[string]any{
+				{"server": map[string]any{"host": "localhost", "port": 8080}},
+				{"server": map[string]any{"port": 9090, "tls": map[string]any{"enabled": true}}},
+				{"debug": true},
+			},
+			expected: map[string]any{
+				"server": map[string]any{"host": "localhost", "port": 9090, "tls": map[string]any{"enabled": true}},
+				"debug":  true,
+			},
+		},
+		{
+			name: "Slice Replace",
+			src: []map[string]any{
+				{"tags": []any{"a", "b"}},
+				{"tags": []any{"c"}},
+			},
+			expected: map[string]any{"tags": []any{"c"}},
+		},
+		{
+			name:     "Slice Append",
+			strategy: MergeStrategy{Slices: SliceAppend},
+			src: []map[string]any{
+				{"tags": []any{"a", "b"}},
+				{"tags": []any{"b", "c"}},
+			},
+			expected: map[string]any{"tags": []any{"a", "b", "b", "c"}},
+		},
+		{
+			name:     "Slice Union",
+			strategy: MergeStrategy{Slices: SliceUnion},
+			src: []map[string]any{
+				{"tags": []any{"a", "b"}},
+				{"tags": []any{"b", "c", "c"}},
+			},
+			expected: map[string]any{"tags": []any{"a", "b", "c"}},
+		},
+		{
+			name:     "Slice Merge By Index",
+			strategy: MergeStrategy{Slices: SliceMergeByIndex},
+			src: []map[string]any{
+				{"ports": []any{map[string]any{"port": 80, "name": "http"}, 1}},
+				{"ports": []any{map[string]any{"port": 8080}, 2, 3}},
+			},
+			expected: map[string]any{"ports": []any{map[string]any{"port": 8080, "name": "http"}, 2, 3}},
+		},
+		{
+			name:     "Slice Merge By Key",
+			strategy: MergeStrategy{Slices: SliceMergeByKey, SliceKey: "name"},
+			src: []map[string]any{
+				{"containers": []any{
+					map[string]any{"name": "app", "image": "app:1", "cpu": 1},
+					map[string]any{"name": "proxy", "image": "proxy:1"},
+				}},
+				{"containers": []any{
+					map[string]any{"name": "app", "image": "app:2"},
+					map[string]any{"name": "metrics", "image": "metrics:1"},
+					"unkeyed",
+				}},
+			},
+			expected: map[string]any{"containers": []any{
+				map[string]any{"name": "app", "image": "app:2", "cpu": 1},
+				map[string]any{"name": "proxy", "image": "proxy:1"},
+				map[string]any{"name": "metrics", "image": "metrics:1"},
+				"unkeyed",
+			}},
+		},
+		{
+			name: "Scalar Resolver",
+			strategy: MergeStrategy{Scalars: func(left, right any) any {
+				if l, ok := left.(int); ok {
+					if r, ok := right.(int); ok {
+						return l + r
+					}
+				}
+				return right
+			}},
+			src: []map[string]any{
+				{"count": 1, "name": "a", "nested": map[string]any{"count": 10}},
+				{"count": 2, "name": "b", "nested": map[string]any{"count": 5}},
+			},
+			expected: map[string]any{"count": 3, "name": "b", "nested": map[string]any{"count": 15}},
+		},
+		{
+			name:     "Nop Scalar Resolver",
+			strategy: MergeStrategy{Scalars: NopResolver[any]()},
+			src: []map[string]any{
+				{"name": "a"},
+				{"name": "b", "other": "c"},
+			},
+			expected: map[string]any{"name": "a", "other": "c"},
+		},
+		{
+			name:     "Type Mismatch Overwrite",
+			strategy: MergeStrategy{TypeMismatch: TypeMismatchOverwrite},
+			src: []map[string]any{
+				{"server": map[string]any{"port": 1}, "tags": "a"},
+				{"server": "localhost", "tags": []any{"b"}},
+			},
+			expected: map[string]any{"server": "localhost", "tags": []any{"b"}},
+		},
+		{
+			name: "Nil Overrides Map",
+			src: []map[string]any{
+				{"server": map[string]any{"port": 1}},
+				{"server": nil},
+			},
+			expected: map[string]any{"server": nil},
+		},
+		{
+			name: "Path Overrides",
+			strategy: MergeStrategy{
+				Slices: SliceMergeByIndex,
+				Paths: map[string]MergeStrategy{
+					"spec.containers":     {Slices: SliceReplace},
+					"spec.args":           {Slices: SliceAppend},
+					"/spec/volumes/*/tag": {Scalars: NopResolver[any]()},
+				},
+			},
+			src: []map[string]any{
+				{"spec": map[string]any{
+					"containers": []any{"a"},
+					"args":       []any{"a"},
+					"volumes":    []any{map[string]any{"tag": "keep"}},
+				}},
+				{"spec": map[string]any{
+					"containers": []any{"b"},
+					"args":       []any{"b"},
+					"volumes":    []any{map[string]any{"tag": "drop", "size": 2}},
+				}},
+			},
+			expected: map[string]any{"spec": map[string]any{
+				"containers": []any{"b"},
+				"args":       []any{"a", "b"},
+				"volumes":    []any{map[string]any{"tag": "keep", "size": 2}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := DeepMerge(test.strategy, test.src...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDeepMerge_PathOverrideMergeByKey(t *testing.T) {
+	strategy := MergeStrategy{
+		Paths: map[string]MergeStrategy{
+			"spec.containers": {Slices: SliceMergeByKey, SliceKey: "name"},
+		},
+	}
+	actual, err := DeepMerge(strategy,
+		map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "app", "env": []any{"A=1"}},
+		}}},
+		map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "app", "env": []any{"B=2"}},
+		}}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"spec": map[string]any{"containers": []any{
+		map[string]any{"name": "app", "env": []any{"B=2"}},
+	}}}, actual)
+}
+
+func TestDeepMerge_TypeMismatchError(t *testing.T) {
+	actual, err := DeepMerge(MergeStrategy{},
+		map[string]any{"server": map[string]any{"tls": map[string]any{"cert": "a"}}},
+		map[string]any{"server": map[string]any{"tls": true}},
+	)
+	assert.Nil(t, actual)
+
+	var typeErr *MergeTypeError
+	assert.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "/server/tls", typeErr.Path)
+	assert.Equal(t, true, typeErr.Right)
+	assert.Equal(t, `cannot merge bool with map[string]interface {} at "/server/tls"`, err.Error())
+}
+
+func TestDeepMerge_DoesNotModifySources(t *testing.T) {
+	first := map[string]any{"server": map[string]any{"port": 1}, "tags": []any{"a"}}
+	second := map[string]any{"server": map[string]any{"host": "x"}, "tags": []any{"b"}}
+
+	merged, err := DeepMerge(MergeStrategy{Slices: SliceAppend}, first, second)
+	assert.NoError(t, err)
+	merged["server"].(map[string]any)["port"] = 2
+	merged["tags"].([]any)[0] = "z"
+
+	assert.Equal(t, map[string]any{"server": map[string]any{"port": 1}, "tags": []any{"a"}}, first)
+	assert.Equal(t, map[string]any{"server": map[string]any{"host": "x"}, "tags": []any{"b"}}, second)
+}
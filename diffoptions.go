@@ -0,0 +1,199 @@
+package maps
+
+import (
+	"math"
+	"reflect"
+)
+
+// DiffOption configures how maps with keys of type K and values of type V are
+// compared by DiffWithOptions, Equal and KeyDiff. The same options can be shared
+// between all three so a single comparison policy can be used throughout a
+// codebase.
+//
+// Options are bound to the key and value types of the maps, so an option that
+// doesn't match the maps being compared is rejected by the compiler. Since the
+// types can't be inferred from the option's arguments alone, they are given
+// explicitly:
+//
+//	Equal(left, right, IgnoreKeys[string, int]("updated_at"))
+type DiffOption[K comparable, V any] func(p *diffPolicy[K, V])
+
+// IgnoreKeys excludes the given keys from the comparison.
+func IgnoreKeys[K comparable, V any](keys ...K) DiffOption[K, V] {
+	set := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return IgnoreKeysMatching[K, V](func(key K) bool {
+		_, ok := set[key]
+		return ok
+	})
+}
+
+// IgnoreKeysMatching excludes all keys that satisfy the predicate from the
+// comparison.
+func IgnoreKeysMatching[K comparable, V any](pred func(key K) bool) DiffOption[K, V] {
+	return func(p *diffPolicy[K, V]) {
+		p.ignore = append(p.ignore, pred)
+	}
+}
+
+// OnlyKeys restricts the comparison to the given keys, all other keys are ignored.
+// If OnlyKeys is provided multiple times the union of the keys is compared.
+func OnlyKeys[K comparable, V any](keys ...K) DiffOption[K, V] {
+	return func(p *diffPolicy[K, V]) {
+		if p.only == nil {
+			p.only = make(map[K]struct{}, len(keys))
+		}
+		for _, k := range keys {
+			p.only[k] = struct{}{}
+		}
+	}
+}
+
+// CompareKeyWith compares the values for the given key using eq instead of the
+// default equality.
+func CompareKeyWith[K comparable, V any](key K, eq func(a, b V) bool) DiffOption[K, V] {
+	return func(p *diffPolicy[K, V]) {
+		if p.comparators == nil {
+			p.comparators = make(map[K]func(a, b V) bool)
+		}
+		p.comparators[key] = eq
+	}
+}
+
+// FloatTolerance treats floating point values as equal if they differ by no more
+// than eps. The tolerance applies to values whose dynamic type is a float, which
+// includes float values stored in maps of any or other interface types.
+func FloatTolerance[K comparable, V any](eps float64) DiffOption[K, V] {
+	return func(p *diffPolicy[K, V]) {
+		p.tolerance = eps
+		p.hasTolerance = true
+	}
+}
+
+// diffPolicy is the configuration built by applying DiffOptions.
+type diffPolicy[K comparable, V any] struct {
+	ignore       []func(key K) bool
+	only         map[K]struct{}
+	comparators  map[K]func(a, b V) bool
+	tolerance    float64
+	hasTolerance bool
+}
+
+func newDiffPolicy[K comparable, V any](opts []DiffOption[K, V]) *diffPolicy[K, V] {
+	policy := &diffPolicy[K, V]{}
+	for _, opt := range opts {
+		opt(policy)
+	}
+	return policy
+}
+
+// includes reports whether the key takes part in the comparison.
+func (p *diffPolicy[K, V]) includes(key K) bool {
+	if p.only != nil {
+		if _, ok := p.only[key]; !ok {
+			return false
+		}
+	}
+	for _, ignore := range p.ignore {
+		if ignore(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal reports whether the values for key are equal, falling back to eq when no
+// option applies to the key or values.
+func (p *diffPolicy[K, V]) equal(key K, a, b V, eq func(a, b V) bool) bool {
+	if comparator, ok := p.comparators[key]; ok {
+		return comparator(a, b)
+	}
+	if p.hasTolerance {
+		if fa, fb, ok := asFloats(a, b); ok {
+			return math.Abs(fa-fb) <= p.tolerance
+		}
+	}
+	return eq(a, b)
+}
+
+// asFloats returns both values as float64 if their dynamic types are floats.
+func asFloats(a, b any) (float64, float64, bool) {
+	fa, ok := asFloat(a)
+	if !ok {
+		return 0, 0, false
+	}
+	fb, ok := asFloat(b)
+	if !ok {
+		return 0, 0, false
+	}
+	return fa, fb, true
+}
+
+func asFloat(v any) (float64, bool) {
+	switch f := v.(type) {
+	case float64:
+		return f, true
+	case float32:
+		return float64(f), true
+	case nil:
+		return 0, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// DiffWithOptions compares two maps like Diff while applying the provided options
+// to control which keys are compared and how their values are compared.
+func DiffWithOptions[M ~map[K]V, K, V comparable](left M, right M, opts ...DiffOption[K, V]) map[K]EntryComparison[V] {
+	if len(opts) == 0 {
+		return Diff(left, right)
+	}
+
+	policy := newDiffPolicy[K, V](opts)
+	eq := func(a, b V) bool { return a == b }
+	res := make(map[K]EntryComparison[V])
+	for key, val := range left {
+		if !policy.includes(key) {
+			continue
+		}
+		otherVal, ok := right[key]
+		if !ok {
+			res[key] = EntryComparison[V]{
+				Left:   val,
+				Right:  otherVal,
+				Reason: DiffMissingRight,
+			}
+			continue
+		}
+		if !policy.equal(key, val, otherVal, eq) {
+			res[key] = EntryComparison[V]{
+				Left:   val,
+				Right:  otherVal,
+				Reason: DiffValue,
+			}
+		}
+	}
+
+	for key, val := range right {
+		if !policy.includes(key) {
+			continue
+		}
+		if _, ok := left[key]; !ok {
+			var zero V
+			res[key] = EntryComparison[V]{
+				Left:   zero,
+				Right:  val,
+				Reason: DiffMissingLeft,
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,149 @@
+package maps
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffWithOptions(t *testing.T) {
+	left := map[string]float64{
+		"cpu":        0.5,
+		"memory":     512,
+		"updated_at": 1000,
+		"version":    1,
+		"latency":    12.0001,
+	}
+	right := map[string]float64{
+		"cpu":        0.75,
+		"memory":     1024,
+		"updated_at": 2000,
+		"version":    2,
+		"latency":    12.0002,
+		"created_at": 3000,
+	}
+
+	tests := []struct {
+		name     string
+		opts     []DiffOption[string, float64]
+		expected map[string]DiffReason
+	}{
+		{
+			name: "No Options",
+			opts: nil,
+			expected: map[string]DiffReason{
+				"cpu":        DiffValue,
+				"memory":     DiffValue,
+				"updated_at": DiffValue,
+				"version":    DiffValue,
+				"latency":    DiffValue,
+				"created_at": DiffMissingLeft,
+			},
+		},
+		{
+			name: "Ignore Keys",
+			opts: []DiffOption[string, float64]{IgnoreKeys[string, float64]("updated_at", "created_at")},
+			expected: map[string]DiffReason{
+				"cpu":     DiffValue,
+				"memory":  DiffValue,
+				"version": DiffValue,
+				"latency": DiffValue,
+			},
+		},
+		{
+			name: "Ignore Keys Matching",
+			opts: []DiffOption[string, float64]{IgnoreKeysMatching[string, float64](func(key string) bool {
+				return strings.HasSuffix(key, "_at")
+			})},
+			expected: map[string]DiffReason{
+				"cpu":     DiffValue,
+				"memory":  DiffValue,
+				"version": DiffValue,
+				"latency": DiffValue,
+			},
+		},
+		{
+			name: "Only Keys",
+			opts: []DiffOption[string, float64]{OnlyKeys[string, float64]("cpu"), OnlyKeys[string, float64]("created_at", "missing")},
+			expected: map[string]DiffReason{
+				"cpu":        DiffValue,
+				"created_at": DiffMissingLeft,
+			},
+		},
+		{
+			name: "Float Tolerance",
+			opts: []DiffOption[string, float64]{FloatTolerance[string, float64](0.001), OnlyKeys[string, float64]("cpu", "latency")},
+			expected: map[string]DiffReason{
+				"cpu": DiffValue,
+			},
+		},
+		{
+			name: "Compare Key With",
+			opts: []DiffOption[string, float64]{
+				OnlyKeys[string, float64]("memory", "version"),
+				CompareKeyWith("memory", func(a, b float64) bool {
+					return a <= b
+				}),
+			},
+			expected: map[string]DiffReason{
+				"version": DiffValue,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := DiffWithOptions(left, right, test.opts...)
+			reasons := make(map[string]DiffReason, len(actual))
+			for k, v := range actual {
+				reasons[k] = v.Reason
+			}
+			assert.Equal(t, test.expected, reasons)
+		})
+	}
+}
+
+func TestDiffWithOptions_FloatToleranceOnInterfaceValues(t *testing.T) {
+	left := map[string]any{"ratio": 0.3333, "name": "a"}
+	right := map[string]any{"ratio": 0.3334, "name": "a"}
+
+	assert.Len(t, DiffWithOptions(left, right), 1)
+	assert.Len(t, DiffWithOptions(left, right, FloatTolerance[string, any](0.001)), 0)
+}
+
+func TestEqual_WithOptions(t *testing.T) {
+	left := map[string]int{"red": 1, "blue": 2, "ts": 100}
+	right := map[string]int{"red": 1, "blue": 2, "ts": 200, "generation": 7}
+
+	assert.False(t, Equal(left, right))
+	assert.False(t, Equal(left, right, IgnoreKeys[string, int]("ts")))
+	assert.True(t, Equal(left, right, IgnoreKeys[string, int]("ts", "generation")))
+	assert.True(t, Equal(left, right, OnlyKeys[string, int]("red", "blue")))
+	assert.True(t, Equal(left, right, IgnoreKeys[string, int]("generation"), CompareKeyWith("ts", func(a, b int) bool {
+		return b-a <= 100
+	})))
+}
+
+func TestKeyDiff_WithOptions(t *testing.T) {
+	left := map[string]int{"red": 1, "orange": 2, "tmp_a": 3}
+	right := map[string]int{"red": 1, "pink": 2, "tmp_b": 3}
+
+	actualLeft, actualRight := KeyDiff(left, right, IgnoreKeysMatching[string, int](func(key string) bool {
+		return strings.HasPrefix(key, "tmp_")
+	}))
+	assert.ElementsMatch(t, []string{"orange"}, actualLeft)
+	assert.ElementsMatch(t, []string{"pink"}, actualRight)
+}
+
+func TestDiffOption_NamedKeyTypes(t *testing.T) {
+	type color string
+	left := map[color]int{"red": 1, "blue": 2}
+	right := map[color]int{"red": 1, "blue": 3}
+
+	assert.False(t, Equal(left, right))
+	assert.True(t, Equal(left, right, IgnoreKeys[color, int]("blue")))
+	assert.Equal(t, map[color]EntryComparison[int]{
+		"blue": {Left: 2, Right: 3, Reason: DiffValue},
+	}, DiffWithOptions(left, right, OnlyKeys[color, int]("blue")))
+}
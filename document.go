@@ -0,0 +1,94 @@
+package maps
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// deepCopyValue returns a deep copy of a document value. Nested map[string]any and
+// []any values are copied recursively, all other values are returned as is.
+func deepCopyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return deepCopyDocument(val)
+	case []any:
+		if val == nil {
+			return val
+		}
+		res := make([]any, len(val))
+		for i, elem := range val {
+			res[i] = deepCopyValue(elem)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// deepCopyDocument returns a deep copy of a nested map[string]any document.
+func deepCopyDocument(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	res := make(map[string]any, len(m))
+	for k, v := range m {
+		res[k] = deepCopyValue(v)
+	}
+	return res
+}
+
+// jsonEqual reports whether two document values are equal using JSON semantics.
+// Numbers are equal if they have the same numeric value regardless of their Go
+// type, so the int 1 decoded from YAML equals the float64 1 decoded from JSON.
+func jsonEqual(a, b any) bool {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			other, ok := bv[k]
+			if !ok || !jsonEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if an, ok := jsonNumber(a); ok {
+		bn, ok := jsonNumber(b)
+		return ok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// jsonNumber returns the value as a float64 if it is a number.
+func jsonNumber(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
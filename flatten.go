@@ -0,0 +1,377 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IndexStyle controls how slice indexes are represented in flattened keys.
+type IndexStyle int
+
+const (
+	// IndexDot represents slice indexes as regular key segments, for example
+	// "servers.0.host". When unflattening, a map whose keys are exactly the
+	// indexes 0 through n-1 becomes a slice.
+	IndexDot IndexStyle = iota
+	// IndexBracket represents slice indexes in brackets, for example
+	// "servers[0].host".
+	IndexBracket
+	// IndexNone doesn't flatten slices, they are kept as values.
+	IndexNone
+)
+
+// FlattenOptions configures Flatten and Unflatten. The zero value flattens with a
+// "." separator, IndexDot indexes, no escaping and no depth limit.
+type FlattenOptions struct {
+	// Separator is placed between the keys of nested maps. Defaults to ".".
+	Separator string
+	// Index controls how slice indexes are represented.
+	Index IndexStyle
+	// Escape escapes occurrences of the separator, backslashes and, with
+	// IndexBracket, opening brackets inside keys with a backslash so keys
+	// containing them survive a round trip. Without escaping such keys are
+	// ambiguous and may conflict.
+	Escape bool
+	// MaxDepth limits the number of segments in a flattened key, values nested
+	// deeper are kept as they are. Zero means no limit.
+	MaxDepth int
+}
+
+func (o FlattenOptions) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+// FlattenConflictError is returned by Flatten and Unflatten when keys conflict.
+type FlattenConflictError struct {
+	// Key is the flattened key that could not be stored.
+	Key string
+	// Other is the flattened key Key conflicts with. When flattening, multiple
+	// nested values can produce the same flattened key, in which case Other equals
+	// Key.
+	Other string
+}
+
+func (e *FlattenConflictError) Error() string {
+	if e.Key == e.Other {
+		return fmt.Sprintf("multiple values flatten to key %q", e.Key)
+	}
+	return fmt.Sprintf("key %q conflicts with key %q", e.Key, e.Other)
+}
+
+// Flatten converts a nested document into a flat map whose keys are the paths to
+// each value, for example {"server": {"port": 80}} becomes {"server.port": 80}.
+// Empty nested maps and slices are kept as values so they survive a round trip
+// through Unflatten.
+//
+// A *FlattenConflictError is returned if multiple values flatten to the same key,
+// which can only happen if keys contain the separator and escaping is disabled.
+func Flatten(m map[string]any, opts FlattenOptions) (map[string]any, error) {
+	f := flattener{opts: opts, sep: opts.separator(), res: make(map[string]any, len(m))}
+	if err := f.flattenMap("", 0, m); err != nil {
+		return nil, err
+	}
+	return f.res, nil
+}
+
+type flattener struct {
+	opts FlattenOptions
+	sep  string
+	res  map[string]any
+}
+
+func (f *flattener) escape(key string) string {
+	if !f.opts.Escape {
+		return key
+	}
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	key = strings.ReplaceAll(key, f.sep, `\`+f.sep)
+	if f.opts.Index == IndexBracket {
+		key = strings.ReplaceAll(key, "[", `\[`)
+	}
+	return key
+}
+
+func (f *flattener) flattenMap(prefix string, depth int, m map[string]any) error {
+	keys := Keys(m)
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := f.escape(k)
+		if prefix != "" {
+			key = prefix + f.sep + key
+		}
+		if err := f.flattenValue(key, depth+1, m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *flattener) flattenValue(key string, depth int, v any) error {
+	if f.opts.MaxDepth <= 0 || depth < f.opts.MaxDepth {
+		switch val := v.(type) {
+		case map[string]any:
+			if len(val) > 0 {
+				return f.flattenMap(key, depth, val)
+			}
+		case []any:
+			if len(val) > 0 && f.opts.Index != IndexNone {
+				for i, elem := range val {
+					var elemKey string
+					if f.opts.Index == IndexBracket {
+						elemKey = key + "[" + strconv.Itoa(i) + "]"
+					} else {
+						elemKey = key + f.sep + strconv.Itoa(i)
+					}
+					if err := f.flattenValue(elemKey, depth+1, elem); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+	}
+
+	if _, ok := f.res[key]; ok {
+		return &FlattenConflictError{Key: key, Other: key}
+	}
+	f.res[key] = deepCopyValue(v)
+	return nil
+}
+
+// Unflatten converts a flat map produced by Flatten back into a nested document
+// using the same options. Keys are split on the separator, honouring escapes if
+// enabled, and intermediate maps and slices are created as needed. The document
+// itself is always a map, even if all top-level keys are indexes. With
+// IndexBracket, gaps between slice indexes are filled with nil. Since flat keys
+// often come from untrusted sources such as environment variables, a slice may have
+// at most maxIndexGap more elements than indexes set, otherwise an error is
+// returned.
+//
+// A *FlattenConflictError is returned if a key is both a value and the parent of
+// other values, for example "a" and "a.b", or is used both as a slice and a map.
+func Unflatten(flat map[string]any, opts FlattenOptions) (map[string]any, error) {
+	u := unflattener{opts: opts, sep: opts.separator()}
+	root := newFlatNode()
+
+	keys := Keys(flat)
+	sort.Strings(keys)
+	for _, key := range keys {
+		segments, err := u.split(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := root.insert(key, segments, deepCopyValue(flat[key])); err != nil {
+			return nil, err
+		}
+	}
+	if err := root.checkGaps(); err != nil {
+		return nil, err
+	}
+	return u.buildMap(root), nil
+}
+
+// maxIndexGap is the number of nil elements Unflatten fills into a slice at most.
+const maxIndexGap = 1024
+
+type unflattener struct {
+	opts FlattenOptions
+	sep  string
+}
+
+type flatSegment struct {
+	key     string
+	bracket bool
+}
+
+func (u *unflattener) split(key string) ([]flatSegment, error) {
+	var segments []flatSegment
+	var sb strings.Builder
+	// pending is true while a segment is being read. A segment that ends with a
+	// bracketed index has already been emitted when the next separator is found.
+	pending := true
+
+	for i := 0; i < len(key); {
+		switch {
+		case u.opts.Escape && key[i] == '\\' && i+1 < len(key):
+			if strings.HasPrefix(key[i+1:], u.sep) {
+				sb.WriteString(u.sep)
+				i += 1 + len(u.sep)
+			} else {
+				sb.WriteByte(key[i+1])
+				i += 2
+			}
+			pending = true
+		case strings.HasPrefix(key[i:], u.sep):
+			if pending {
+				segments = append(segments, flatSegment{key: sb.String()})
+			}
+			sb.Reset()
+			pending = true
+			i += len(u.sep)
+		case u.opts.Index == IndexBracket && key[i] == '[':
+			end := strings.IndexByte(key[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in key %q", key)
+			}
+			index := key[i+1 : i+end]
+			// Only canonical indexes are accepted so "+1" and "01" can't silently
+			// collide with "1".
+			if idx, err := strconv.Atoi(index); err != nil || idx < 0 || strconv.Itoa(idx) != index {
+				return nil, fmt.Errorf("invalid index %q in key %q", index, key)
+			}
+			if pending && (sb.Len() > 0 || len(segments) == 0) {
+				segments = append(segments, flatSegment{key: sb.String()})
+			}
+			sb.Reset()
+			segments = append(segments, flatSegment{key: index, bracket: true})
+			pending = false
+			i += end + 1
+		default:
+			sb.WriteByte(key[i])
+			pending = true
+			i++
+		}
+	}
+	if pending {
+		segments = append(segments, flatSegment{key: sb.String()})
+	}
+	return segments, nil
+}
+
+// flatNode is an intermediate container built by Unflatten before it is known
+// whether it becomes a map or a slice.
+type flatNode struct {
+	children map[string]any
+	// leafKeys holds the flattened key that set each leaf child.
+	leafKeys map[string]string
+	// bracketKey and plainKey hold the first flattened key that addressed the node
+	// with a bracketed index or a plain key respectively.
+	bracketKey string
+	plainKey   string
+	// maxIndex and maxIndexKey hold the largest bracketed index of a child and the
+	// flattened key that set it.
+	maxIndex    int
+	maxIndexKey string
+}
+
+func newFlatNode() *flatNode {
+	return &flatNode{children: make(map[string]any), leafKeys: make(map[string]string)}
+}
+
+func (n *flatNode) insert(key string, segments []flatSegment, val any) error {
+	seg := segments[0]
+	if seg.bracket {
+		if n.plainKey != "" {
+			return &FlattenConflictError{Key: key, Other: n.plainKey}
+		}
+		if n.bracketKey == "" {
+			n.bracketKey = key
+		}
+		if idx, _ := strconv.Atoi(seg.key); idx > n.maxIndex || n.maxIndexKey == "" {
+			n.maxIndex, n.maxIndexKey = idx, key
+		}
+	} else {
+		if n.bracketKey != "" {
+			return &FlattenConflictError{Key: key, Other: n.bracketKey}
+		}
+		if n.plainKey == "" {
+			n.plainKey = key
+		}
+	}
+
+	child, exists := n.children[seg.key]
+	if len(segments) == 1 {
+		if exists {
+			return &FlattenConflictError{Key: key, Other: n.firstKey(seg.key)}
+		}
+		n.children[seg.key] = val
+		n.leafKeys[seg.key] = key
+		return nil
+	}
+
+	childNode, ok := child.(*flatNode)
+	if exists && !ok {
+		return &FlattenConflictError{Key: key, Other: n.leafKeys[seg.key]}
+	}
+	if !exists {
+		childNode = newFlatNode()
+		n.children[seg.key] = childNode
+	}
+	return childNode.insert(key, segments[1:], val)
+}
+
+// firstKey returns a flattened key that set a value beneath the child.
+func (n *flatNode) firstKey(child string) string {
+	if key, ok := n.leafKeys[child]; ok {
+		return key
+	}
+	node := n.children[child].(*flatNode)
+	if node.plainKey != "" {
+		return node.plainKey
+	}
+	return node.bracketKey
+}
+
+// checkGaps returns an error if a slice beneath the node would be filled with more
+// than maxIndexGap nil elements.
+func (n *flatNode) checkGaps() error {
+	if n.bracketKey != "" && n.maxIndex >= len(n.children)+maxIndexGap {
+		return fmt.Errorf("index %d in key %q is out of range", n.maxIndex, n.maxIndexKey)
+	}
+	for _, child := range n.children {
+		if childNode, ok := child.(*flatNode); ok {
+			if err := childNode.checkGaps(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (u *unflattener) build(v any) any {
+	n, ok := v.(*flatNode)
+	if !ok {
+		return v
+	}
+
+	if n.bracketKey != "" || (u.opts.Index == IndexDot && n.plainKey != "" && isIndexSequence(n.children)) {
+		size := 0
+		for k := range n.children {
+			idx, _ := strconv.Atoi(k)
+			if idx+1 > size {
+				size = idx + 1
+			}
+		}
+		res := make([]any, size)
+		for k, child := range n.children {
+			idx, _ := strconv.Atoi(k)
+			res[idx] = u.build(child)
+		}
+		return res
+	}
+	return u.buildMap(n)
+}
+
+func (u *unflattener) buildMap(n *flatNode) map[string]any {
+	res := make(map[string]any, len(n.children))
+	for k, child := range n.children {
+		res[k] = u.build(child)
+	}
+	return res
+}
+
+// isIndexSequence reports whether the keys are exactly the indexes 0 through n-1.
+func isIndexSequence(children map[string]any) bool {
+	for k := range children {
+		idx, err := strconv.Atoi(k)
+		if err != nil || idx < 0 || idx >= len(children) || strconv.Itoa(idx) != k {
+			return false
+		}
+	}
+	return len(children) > 0
+}
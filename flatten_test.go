@@ -0,0 +1,284 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	doc := map[string]any{
+		"server": map[string]any{
+			"host": "localhost",
+			"port": 8080,
+		},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			"b",
+		},
+		"labels": map[string]any{},
+		"tags":   []any{},
+		"name":   "api",
+	}
+
+	tests := []struct {
+		name     string
+		opts     FlattenOptions
+		expected map[string]any
+	}{
+		{
+			name: "Defaults",
+			expected: map[string]any{
+				"server.host":    "localhost",
+				"server.port":    8080,
+				"servers.0.host": "a",
+				"servers.1":      "b",
+				"labels":         map[string]any{},
+				"tags":           []any{},
+				"name":           "api",
+			},
+		},
+		{
+			name: "Bracket Indexes And Custom Separator",
+			opts: FlattenOptions{Separator: "__", Index: IndexBracket},
+			expected: map[string]any{
+				"server__host":     "localhost",
+				"server__port":     8080,
+				"servers[0]__host": "a",
+				"servers[1]":       "b",
+				"labels":           map[string]any{},
+				"tags":             []any{},
+				"name":             "api",
+			},
+		},
+		{
+			name: "No Indexes",
+			opts: FlattenOptions{Index: IndexNone},
+			expected: map[string]any{
+				"server.host": "localhost",
+				"server.port": 8080,
+				"servers":     []any{map[string]any{"host": "a"}, "b"},
+				"labels":      map[string]any{},
+				"tags":        []any{},
+				"name":        "api",
+			},
+		},
+		{
+			name: "Max Depth",
+			opts: FlattenOptions{MaxDepth: 1},
+			expected: map[string]any{
+				"server":  map[string]any{"host": "localhost", "port": 8080},
+				"servers": []any{map[string]any{"host": "a"}, "b"},
+				"labels":  map[string]any{},
+				"tags":    []any{},
+				"name":    "api",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flat, err := Flatten(doc, test.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, flat)
+
+			nested, err := Unflatten(flat, test.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, doc, nested)
+		})
+	}
+}
+
+func TestFlatten_Escape(t *testing.T) {
+	doc := map[string]any{
+		"example.com": map[string]any{"path\\to": 1, "a[0]": 2},
+	}
+
+	flat, err := Flatten(doc, FlattenOptions{Escape: true, Index: IndexBracket})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		`example\.com.path\\to`: 1,
+		`example\.com.a\[0]`:    2,
+	}, flat)
+
+	nested, err := Unflatten(flat, FlattenOptions{Escape: true, Index: IndexBracket})
+	assert.NoError(t, err)
+	assert.Equal(t, doc, nested)
+}
+
+func TestFlatten_Conflict(t *testing.T) {
+	_, err := Flatten(map[string]any{
+		"a.b": 1,
+		"a":   map[string]any{"b": 2},
+	}, FlattenOptions{})
+	assert.Equal(t, &FlattenConflictError{Key: "a.b", Other: "a.b"}, err)
+	assert.EqualError(t, err, `multiple values flatten to key "a.b"`)
+
+	flat, err := Flatten(map[string]any{
+		"a.b": 1,
+		"a":   map[string]any{"b": 2},
+	}, FlattenOptions{Escape: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{`a\.b`: 1, "a.b": 2}, flat)
+}
+
+func TestFlatten_NumericKeysRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		opts FlattenOptions
+		doc  map[string]any
+	}{
+		{name: "Dot Indexes", doc: map[string]any{"0": "x", "1": "y"}},
+		{name: "Bracket Indexes", opts: FlattenOptions{Index: IndexBracket}, doc: map[string]any{"0": "x", "1": []any{"y"}}},
+		{name: "Nested", doc: map[string]any{"0": map[string]any{"a": "x"}, "1": []any{"y", "z"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flat, err := Flatten(test.doc, test.opts)
+			assert.NoError(t, err)
+			nested, err := Unflatten(flat, test.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, test.doc, nested)
+		})
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		name     string
+		flat     map[string]any
+		opts     FlattenOptions
+		expected map[string]any
+	}{
+		{
+			name: "Dot Indexes Must Be A Sequence",
+			flat: map[string]any{"a.0": "x", "a.1": "y", "b.0": "x", "b.2": "z", "c.01": "x"},
+			expected: map[string]any{
+				"a": []any{"x", "y"},
+				"b": map[string]any{"0": "x", "2": "z"},
+				"c": map[string]any{"01": "x"},
+			},
+		},
+		{
+			name:     "Bracket Gaps Are Filled With Nil",
+			flat:     map[string]any{"a[2]": "z", "a[0]": "x"},
+			opts:     FlattenOptions{Index: IndexBracket},
+			expected: map[string]any{"a": []any{"x", nil, "z"}},
+		},
+		{
+			name:     "Nested Bracket Indexes",
+			flat:     map[string]any{"m[0][1].k": true},
+			opts:     FlattenOptions{Index: IndexBracket},
+			expected: map[string]any{"m": []any{[]any{nil, map[string]any{"k": true}}}},
+		},
+		{
+			name:     "Numeric Top-Level Keys Stay Keys",
+			flat:     map[string]any{"0": "x", "1.0": "y", "1.1": "z"},
+			expected: map[string]any{"0": "x", "1": []any{"y", "z"}},
+		},
+		{
+			name:     "Largest Allowed Gap",
+			flat:     map[string]any{"a[1024]": 1},
+			opts:     FlattenOptions{Index: IndexBracket},
+			expected: map[string]any{"a": append(make([]any, 1024), 1)},
+		},
+		{
+			name:     "Numeric Keys Stay Keys Without Index Style",
+			flat:     map[string]any{"a.0": "x"},
+			opts:     FlattenOptions{Index: IndexNone},
+			expected: map[string]any{"a": map[string]any{"0": "x"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nested, err := Unflatten(test.flat, test.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, nested)
+		})
+	}
+}
+
+func TestUnflatten_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		flat map[string]any
+		opts FlattenOptions
+		err  string
+	}{
+		{
+			name: "Value And Parent",
+			flat: map[string]any{"a": 1, "a.b": 2},
+			err:  `key "a.b" conflicts with key "a"`,
+		},
+		{
+			name: "Custom Separator",
+			flat: map[string]any{"a/b": 1, "a": 2},
+			opts: FlattenOptions{Separator: "/"},
+			err:  `key "a/b" conflicts with key "a"`,
+		},
+		{
+			name: "Slice And Map",
+			flat: map[string]any{"a[0]": 1, "a.b": 2},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `key "a[0]" conflicts with key "a.b"`,
+		},
+		{
+			name: "Same Index Twice",
+			flat: map[string]any{"a[0]": 1, "a.[0]": 2},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `key "a[0]" conflicts with key "a.[0]"`,
+		},
+		{
+			name: "Invalid Index",
+			flat: map[string]any{"a[x]": 1},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `invalid index "x" in key "a[x]"`,
+		},
+		{
+			name: "Non-Canonical Index",
+			flat: map[string]any{"a[1]": "x", "a[+1]": "y", "a[01]": "z"},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `invalid index "+1" in key "a[+1]"`,
+		},
+		{
+			name: "Leading Zero Index",
+			flat: map[string]any{"a[1]": "x", "a[01]": "z"},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `invalid index "01" in key "a[01]"`,
+		},
+		{
+			name: "Negative Index",
+			flat: map[string]any{"a[-1]": 1},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `invalid index "-1" in key "a[-1]"`,
+		},
+		{
+			name: "Index Out Of Range",
+			flat: map[string]any{"a[100000000000000]": 1, "a[0]": 2},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `index 100000000000000 in key "a[100000000000000]" is out of range`,
+		},
+		{
+			name: "Nested Index Out Of Range",
+			flat: map[string]any{"a[0][1026]": 1, "a[0][0]": 2},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `index 1026 in key "a[0][1026]" is out of range`,
+		},
+		{
+			name: "Unterminated Index",
+			flat: map[string]any{"a[0": 1},
+			opts: FlattenOptions{Index: IndexBracket},
+			err:  `unterminated index in key "a[0"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nested, err := Unflatten(test.flat, test.opts)
+			assert.Nil(t, nested)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrKeyNotFound is returned, wrapped with the key, by the typed getters such as
+// GetString and GetInt when the key doesn't exist in the map.
+var ErrKeyNotFound = errors.New("not found")
+
+// getTyped looks up the key and converts its value, wrapping failures with the key.
+func getTyped[M ~map[string]any, T any](m M, key string, convert func(v any) (T, error)) (T, error) {
+	var zero T
+	val, ok := m[key]
+	if !ok {
+		return zero, fmt.Errorf("key %q: %w", key, ErrKeyNotFound)
+	}
+	res, err := convert(val)
+	if err != nil {
+		return zero, newConversionError(key, val, reflect.TypeOf(&zero).Elem(), err)
+	}
+	return res, nil
+}
+
+// weakScalar returns a conversion function to a scalar type T using weak typing.
+func weakScalar[T any]() func(v any) (T, error) {
+	return func(v any) (T, error) {
+		var zero T
+		res, err := convertScalar(v, reflect.TypeOf(zero), true)
+		if err != nil {
+			return zero, err
+		}
+		return res.Interface().(T), nil
+	}
+}
+
+// GetString returns the value of the key as a string. Numbers and bools are
+// formatted as strings.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetString[M ~map[string]any](m M, key string) (string, error) {
+	return getTyped(m, key, weakScalar[string]())
+}
+
+// GetStringOrDefault returns the value of the key as a string like GetString, or the
+// default value if the key doesn't exist or can't be converted.
+func GetStringOrDefault[M ~map[string]any](m M, key string, defaultVal string) string {
+	if val, err := GetString(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustString returns the value of the key as a string like GetString, or panics if
+// the key doesn't exist or can't be converted.
+func MustString[M ~map[string]any](m M, key string) string {
+	val, err := GetString(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// GetInt returns the value of the key as an int. Numbers of any type convert as long
+// as their value is preserved, so the float64 80 decoded from JSON converts but 1.5
+// doesn't. Strings are parsed, so values sourced from environment variables convert
+// as well, and bools convert to 1 and 0.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetInt[M ~map[string]any](m M, key string) (int, error) {
+	return getTyped(m, key, weakScalar[int]())
+}
+
+// GetIntOrDefault returns the value of the key as an int like GetInt, or the default
+// value if the key doesn't exist or can't be converted.
+func GetIntOrDefault[M ~map[string]any](m M, key string, defaultVal int) int {
+	if val, err := GetInt(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustInt returns the value of the key as an int like GetInt, or panics if the key
+// doesn't exist or can't be converted.
+func MustInt[M ~map[string]any](m M, key string) int {
+	val, err := GetInt(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// GetInt64 returns the value of the key as an int64, see GetInt for the supported
+// conversions.
+func GetInt64[M ~map[string]any](m M, key string) (int64, error) {
+	return getTyped(m, key, weakScalar[int64]())
+}
+
+// GetInt64OrDefault returns the value of the key as an int64 like GetInt64, or the
+// default value if the key doesn't exist or can't be converted.
+func GetInt64OrDefault[M ~map[string]any](m M, key string, defaultVal int64) int64 {
+	if val, err := GetInt64(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustInt64 returns the value of the key as an int64 like GetInt64, or panics if the
+// key doesn't exist or can't be converted.
+func MustInt64[M ~map[string]any](m M, key string) int64 {
+	val, err := GetInt64(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// GetFloat returns the value of the key as a float64. Numbers of any type, including
+// json.Number, convert and strings are parsed.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetFloat[M ~map[string]any](m M, key string) (float64, error) {
+	return getTyped(m, key, weakScalar[float64]())
+}
+
+// GetFloatOrDefault returns the value of the key as a float64 like GetFloat, or the
+// default value if the key doesn't exist or can't be converted.
+func GetFloatOrDefault[M ~map[string]any](m M, key string, defaultVal float64) float64 {
+	if val, err := GetFloat(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustFloat returns the value of the key as a float64 like GetFloat, or panics if
+// the key doesn't exist or can't be converted.
+func MustFloat[M ~map[string]any](m M, key string) float64 {
+	val, err := GetFloat(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// GetBool returns the value of the key as a bool. Strings are parsed with
+// strconv.ParseBool and numbers are true if they aren't zero.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetBool[M ~map[string]any](m M, key string) (bool, error) {
+	return getTyped(m, key, weakScalar[bool]())
+}
+
+// GetBoolOrDefault returns the value of the key as a bool like GetBool, or the
+// default value if the key doesn't exist or can't be converted.
+func GetBoolOrDefault[M ~map[string]any](m M, key string, defaultVal bool) bool {
+	if val, err := GetBool(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustBool returns the value of the key as a bool like GetBool, or panics if the key
+// doesn't exist or can't be converted.
+func MustBool[M ~map[string]any](m M, key string) bool {
+	val, err := GetBool(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// GetDuration returns the value of the key as a time.Duration. Strings are parsed
+// with time.ParseDuration, such as "1m30s", and integral numbers are interpreted as
+// nanoseconds.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetDuration[M ~map[string]any](m M, key string) (time.Duration, error) {
+	return getTyped(m, key, weakScalar[time.Duration]())
+}
+
+// GetDurationOrDefault returns the value of the key as a time.Duration like
+// GetDuration, or the default value if the key doesn't exist or can't be converted.
+func GetDurationOrDefault[M ~map[string]any](m M, key string, defaultVal time.Duration) time.Duration {
+	if val, err := GetDuration(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustDuration returns the value of the key as a time.Duration like GetDuration, or
+// panics if the key doesn't exist or can't be converted.
+func MustDuration[M ~map[string]any](m M, key string) time.Duration {
+	val, err := GetDuration(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// GetTime returns the value of the key as a time.Time. Strings are parsed using the
+// layouts in order, defaulting to time.RFC3339, and integral numbers are interpreted
+// as seconds since the Unix epoch.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetTime[M ~map[string]any](m M, key string, layouts ...string) (time.Time, error) {
+	return getTyped(m, key, func(v any) (time.Time, error) {
+		return toTime(v, layouts)
+	})
+}
+
+// GetTimeOrDefault returns the value of the key as a time.Time like GetTime, or the
+// default value if the key doesn't exist or can't be converted.
+func GetTimeOrDefault[M ~map[string]any](m M, key string, defaultVal time.Time, layouts ...string) time.Time {
+	if val, err := GetTime(m, key, layouts...); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustTime returns the value of the key as a time.Time like GetTime, or panics if
+// the key doesn't exist or can't be converted.
+func MustTime[M ~map[string]any](m M, key string, layouts ...string) time.Time {
+	val, err := GetTime(m, key, layouts...)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func toTime(v any, layouts []string) (time.Time, error) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, nil
+	case string:
+		if len(layouts) == 0 {
+			layouts = []string{time.RFC3339}
+		}
+		var err error
+		for _, layout := range layouts {
+			var t time.Time
+			if t, err = time.Parse(layout, val); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, err
+	}
+
+	secs, err := toInt64(v, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// GetStringSlice returns the value of the key as a []string. Slices of any type
+// convert if all their elements convert to strings as described by GetString.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value can't be converted.
+func GetStringSlice[M ~map[string]any](m M, key string) ([]string, error) {
+	return getTyped(m, key, toStringSlice)
+}
+
+// GetStringSliceOrDefault returns the value of the key as a []string like
+// GetStringSlice, or the default value if the key doesn't exist or can't be
+// converted.
+func GetStringSliceOrDefault[M ~map[string]any](m M, key string, defaultVal []string) []string {
+	if val, err := GetStringSlice(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustStringSlice returns the value of the key as a []string like GetStringSlice, or
+// panics if the key doesn't exist or can't be converted.
+func MustStringSlice[M ~map[string]any](m M, key string) []string {
+	val, err := GetStringSlice(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func toStringSlice(v any) ([]string, error) {
+	if s, ok := v.([]string); ok {
+		return s, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, errIncompatibleType
+	}
+	res := make([]string, rv.Len())
+	for i := range res {
+		s, err := toString(rv.Index(i).Interface(), true)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		res[i] = s
+	}
+	return res, nil
+}
+
+// GetMap returns the value of the key as a map[string]any, such as a nested object
+// of a decoded JSON document. Other maps with string keys are copied into a new
+// map[string]any.
+//
+// An error wrapping ErrKeyNotFound is returned if the key doesn't exist, and a
+// *ConversionError if the value isn't a map with string keys.
+func GetMap[M ~map[string]any](m M, key string) (map[string]any, error) {
+	return getTyped(m, key, toStringMap)
+}
+
+// GetMapOrDefault returns the value of the key as a map[string]any like GetMap, or
+// the default value if the key doesn't exist or isn't a map.
+func GetMapOrDefault[M ~map[string]any](m M, key string, defaultVal map[string]any) map[string]any {
+	if val, err := GetMap(m, key); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// MustMap returns the value of the key as a map[string]any like GetMap, or panics if
+// the key doesn't exist or isn't a map.
+func MustMap[M ~map[string]any](m M, key string) map[string]any {
+	val, err := GetMap(m, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func toStringMap(v any) (map[string]any, error) {
+	if m, ok := v.(map[string]any); ok {
+		return m, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, errIncompatibleType
+	}
+	res := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		res[iter.Key().String()] = iter.Value().Interface()
+	}
+	return res, nil
+}
@@ -0,0 +1,167 @@
+package maps
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetters(t *testing.T) {
+	doc := decodeJSON[map[string]any](t, `{
+		"name": "api",
+		"port": 8080,
+		"ratio": 0.25,
+		"debug": true,
+		"timeout": "1m30s",
+		"started": "2024-01-02T03:04:05Z",
+		"hosts": ["a", "b"],
+		"labels": {"env": "prod"}
+	}`)
+	env := map[string]any{
+		"PORT":    "9090",
+		"RATIO":   "0.5",
+		"DEBUG":   "1",
+		"COUNT":   json.Number("42"),
+		"EPOCH":   int64(1700000000),
+		"DATE":    "02/01/2024",
+		"TAGS":    []any{"x", 1, true},
+		"SHARDS":  map[string]int{"a": 1},
+		"NAME":    12.5,
+		"TIMEOUT": int64(time.Second),
+	}
+
+	assert.Equal(t, "api", MustString(doc, "name"))
+	assert.Equal(t, "12.5", MustString(env, "NAME"))
+	assert.Equal(t, 8080, MustInt(doc, "port"))
+	assert.Equal(t, 9090, MustInt(env, "PORT"))
+	assert.Equal(t, int64(42), MustInt64(env, "COUNT"))
+	assert.Equal(t, 0.25, MustFloat(doc, "ratio"))
+	assert.Equal(t, 0.5, MustFloat(env, "RATIO"))
+	assert.Equal(t, true, MustBool(doc, "debug"))
+	assert.Equal(t, true, MustBool(env, "DEBUG"))
+	assert.Equal(t, 90*time.Second, MustDuration(doc, "timeout"))
+	assert.Equal(t, time.Second, MustDuration(env, "TIMEOUT"))
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), MustTime(doc, "started"))
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), MustTime(env, "DATE", time.RFC3339, "02/01/2006"))
+	assert.Equal(t, time.Unix(1700000000, 0), MustTime(env, "EPOCH"))
+	assert.Equal(t, []string{"a", "b"}, MustStringSlice(doc, "hosts"))
+	assert.Equal(t, []string{"x", "1", "true"}, MustStringSlice(env, "TAGS"))
+	assert.Equal(t, map[string]any{"env": "prod"}, MustMap(doc, "labels"))
+	assert.Equal(t, map[string]any{"a": 1}, MustMap(env, "SHARDS"))
+}
+
+func TestGetters_Errors(t *testing.T) {
+	m := map[string]any{
+		"port":    "http",
+		"ratio":   1.5,
+		"debug":   "maybe",
+		"timeout": "soon",
+		"started": "yesterday",
+		"hosts":   []any{"a", map[string]any{}},
+		"labels":  []any{},
+		"name":    nil,
+	}
+
+	tests := []struct {
+		name string
+		get  func() error
+		err  string
+	}{
+		{
+			name: "Missing Key",
+			get:  func() error { _, err := GetString(m, "missing"); return err },
+			err:  `key "missing": not found`,
+		},
+		{
+			name: "Nil Value",
+			get:  func() error { _, err := GetString(m, "name"); return err },
+			err:  `key "name": cannot convert <nil> (<nil>) to string`,
+		},
+		{
+			name: "Invalid Int",
+			get:  func() error { _, err := GetInt(m, "port"); return err },
+			err:  `key "port": cannot convert "http" (string) to int: invalid syntax`,
+		},
+		{
+			name: "Fractional Int64",
+			get:  func() error { _, err := GetInt64(m, "ratio"); return err },
+			err:  `key "ratio": cannot convert 1.5 (float64) to int64: not an integer`,
+		},
+		{
+			name: "Invalid Float",
+			get:  func() error { _, err := GetFloat(m, "hosts"); return err },
+			err:  `key "hosts": cannot convert [a map[]] ([]interface {}) to float64`,
+		},
+		{
+			name: "Invalid Bool",
+			get:  func() error { _, err := GetBool(m, "debug"); return err },
+			err:  `key "debug": cannot convert "maybe" (string) to bool: invalid syntax`,
+		},
+		{
+			name: "Invalid Duration",
+			get:  func() error { _, err := GetDuration(m, "timeout"); return err },
+			err:  `key "timeout": cannot convert "soon" (string) to time.Duration: time: invalid duration "soon"`,
+		},
+		{
+			name: "Invalid Time",
+			get:  func() error { _, err := GetTime(m, "started", time.DateOnly); return err },
+			err:  `key "started": cannot convert "yesterday" (string) to time.Time: parsing time "yesterday" as "2006-01-02": cannot parse "yesterday" as "2006"`,
+		},
+		{
+			name: "Invalid String Slice",
+			get:  func() error { _, err := GetStringSlice(m, "hosts"); return err },
+			err:  `key "hosts": cannot convert [a map[]] ([]interface {}) to []string`,
+		},
+		{
+			name: "Invalid Map",
+			get:  func() error { _, err := GetMap(m, "labels"); return err },
+			err:  `key "labels": cannot convert [] ([]interface {}) to map[string]interface {}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.get()
+			assert.EqualError(t, err, test.err)
+
+			var convErr *ConversionError
+			if errors.As(err, &convErr) {
+				assert.NotEqual(t, "missing", convErr.Key)
+			} else {
+				assert.ErrorIs(t, err, ErrKeyNotFound)
+			}
+		})
+	}
+}
+
+func TestGetters_OrDefault(t *testing.T) {
+	m := map[string]any{"port": "http", "debug": false}
+	now := time.Now()
+
+	assert.Equal(t, "x", GetStringOrDefault(m, "missing", "x"))
+	assert.Equal(t, "http", GetStringOrDefault(m, "port", "x"))
+	assert.Equal(t, 80, GetIntOrDefault(m, "port", 80))
+	assert.Equal(t, int64(80), GetInt64OrDefault(m, "port", 80))
+	assert.Equal(t, 0.5, GetFloatOrDefault(m, "missing", 0.5))
+	assert.Equal(t, false, GetBoolOrDefault(m, "debug", true))
+	assert.Equal(t, time.Second, GetDurationOrDefault(m, "port", time.Second))
+	assert.Equal(t, now, GetTimeOrDefault(m, "port", now))
+	assert.Equal(t, []string{"a"}, GetStringSliceOrDefault(m, "missing", []string{"a"}))
+	assert.Equal(t, map[string]any{}, GetMapOrDefault(m, "port", map[string]any{}))
+}
+
+func TestGetters_Must(t *testing.T) {
+	m := map[string]any{"port": "http"}
+	assert.PanicsWithError(t, `key "missing": not found`, func() { MustString(m, "missing") })
+	assert.PanicsWithError(t, `key "port": cannot convert "http" (string) to int: invalid syntax`, func() { MustInt(m, "port") })
+	assert.Panics(t, func() { MustInt64(m, "port") })
+	assert.Panics(t, func() { MustFloat(m, "port") })
+	assert.Panics(t, func() { MustBool(m, "port") })
+	assert.Panics(t, func() { MustDuration(m, "port") })
+	assert.Panics(t, func() { MustTime(m, "port") })
+	assert.Panics(t, func() { MustStringSlice(m, "port") })
+	assert.Panics(t, func() { MustMap(m, "port") })
+}
@@ -0,0 +1,299 @@
+package maps
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Hasher defines the identity of keys of a HashMap. Keys that are Equal must have
+// the same Hash.
+type Hasher[K any] struct {
+	Hash  func(key K) uint64
+	Equal func(a, b K) bool
+}
+
+// BytesHasher returns a Hasher for byte slices comparing their contents.
+func BytesHasher() Hasher[[]byte] {
+	seed := maphash.MakeSeed()
+	return Hasher[[]byte]{
+		Hash: func(key []byte) uint64 {
+			return maphash.Bytes(seed, key)
+		},
+		Equal: func(a, b []byte) bool {
+			return string(a) == string(b)
+		},
+	}
+}
+
+// StringSliceHasher returns a Hasher for string slices comparing their elements.
+func StringSliceHasher() Hasher[[]string] {
+	seed := maphash.MakeSeed()
+	return Hasher[[]string]{
+		Hash: func(key []string) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			for _, s := range key {
+				writeHashLength(&h, len(s))
+				h.WriteString(s)
+			}
+			return h.Sum64()
+		},
+		Equal: func(a, b []string) bool {
+			if len(a) != len(b) {
+				return false
+			}
+			for i := range a {
+				if a[i] != b[i] {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// ProjectionHasher returns a Hasher identifying keys by a comparable projection,
+// such as a subset of the fields of a struct:
+//
+//	hasher := ProjectionHasher(func(u User) [2]string { return [2]string{u.Org, u.Name} })
+//
+// Keys are equal if their projections are equal.
+func ProjectionHasher[K any, P comparable](project func(key K) P) Hasher[K] {
+	seed := maphash.MakeSeed()
+	return Hasher[K]{
+		Hash: func(key K) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			writeHashValue(&h, reflect.ValueOf(project(key)))
+			return h.Sum64()
+		},
+		Equal: func(a, b K) bool {
+			return project(a) == project(b)
+		},
+	}
+}
+
+func writeHashLength(h *maphash.Hash, n int) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(n))
+	_, _ = h.Write(buf[:])
+}
+
+// writeHashValue writes a comparable value to the hash so that values equal under
+// == produce the same hash.
+func writeHashValue(h *maphash.Hash, v reflect.Value) {
+	if !v.IsValid() {
+		_ = h.WriteByte(0)
+		return
+	}
+	var buf [8]byte
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(buf[:], uint64(v.Int()))
+		_, _ = h.Write(buf[:])
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.LittleEndian.PutUint64(buf[:], v.Uint())
+		_, _ = h.Write(buf[:])
+	case reflect.Float32, reflect.Float64:
+		writeHashFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeHashFloat(h, real(v.Complex()))
+		writeHashFloat(h, imag(v.Complex()))
+	case reflect.String:
+		writeHashLength(h, v.Len())
+		h.WriteString(v.String())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeHashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeHashValue(h, v.Field(i))
+		}
+	case reflect.Interface:
+		writeHashValue(h, v.Elem())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		binary.LittleEndian.PutUint64(buf[:], uint64(v.Pointer()))
+		_, _ = h.Write(buf[:])
+	default:
+		// Values of other kinds aren't comparable and can't be projections.
+		panic("maps: cannot hash value of type " + v.Type().String())
+	}
+}
+
+func writeHashFloat(h *maphash.Hash, f float64) {
+	if f == 0 {
+		// Positive and negative zero are equal.
+		f = 0
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+	_, _ = h.Write(buf[:])
+}
+
+type slotState uint8
+
+const (
+	slotEmpty slotState = iota
+	slotOccupied
+	slotDeleted
+)
+
+type hashSlot[K, V any] struct {
+	hash  uint64
+	state slotState
+	key   K
+	val   V
+}
+
+// HashMap is a map whose keys are identified by a Hasher rather than by ==, so keys
+// may be of types that aren't comparable such as slices, or of types where only
+// some fields define identity. It is implemented as a hash table with open
+// addressing and linear probing.
+//
+// HashMap implements Map so it can be used with KeysOf, FilterInto, DiffOf and the
+// other functions operating on a Map. The zero value is not usable, use NewHashMap.
+// A HashMap is not safe for concurrent use.
+type HashMap[K any, V any] struct {
+	hasher Hasher[K]
+	slots  []hashSlot[K, V]
+	len    int
+	// used counts the occupied and deleted slots, which both lengthen probes.
+	used int
+}
+
+// NewHashMap creates an empty HashMap using the Hasher to identify keys.
+func NewHashMap[K, V any](hasher Hasher[K]) *HashMap[K, V] {
+	return &HashMap[K, V]{hasher: hasher}
+}
+
+// find returns the index of the slot holding the key, or -1 if the key doesn't
+// exist.
+func (m *HashMap[K, V]) find(key K, hash uint64) int {
+	if len(m.slots) == 0 {
+		return -1
+	}
+	mask := uint64(len(m.slots) - 1)
+	for i := hash & mask; ; i = (i + 1) & mask {
+		slot := &m.slots[i]
+		switch {
+		case slot.state == slotEmpty:
+			return -1
+		case slot.state == slotOccupied && slot.hash == hash && m.hasher.Equal(slot.key, key):
+			return int(i)
+		}
+	}
+}
+
+// Get returns the value of the key and whether it exists.
+func (m *HashMap[K, V]) Get(key K) (V, bool) {
+	if i := m.find(key, m.hasher.Hash(key)); i >= 0 {
+		return m.slots[i].val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has reports whether the key exists.
+func (m *HashMap[K, V]) Has(key K) bool {
+	return m.find(key, m.hasher.Hash(key)) >= 0
+}
+
+// Set sets the value of the key. If an equal key already exists its value is
+// replaced and the existing key kept.
+func (m *HashMap[K, V]) Set(key K, val V) {
+	hash := m.hasher.Hash(key)
+	if i := m.find(key, hash); i >= 0 {
+		m.slots[i].val = val
+		return
+	}
+
+	// The table is kept at most three quarters full so probes stay short and always
+	// reach an empty slot.
+	if (m.used+1)*4 > len(m.slots)*3 {
+		m.resize()
+	}
+	mask := uint64(len(m.slots) - 1)
+	i := hash & mask
+	for m.slots[i].state == slotOccupied {
+		i = (i + 1) & mask
+	}
+	if m.slots[i].state == slotEmpty {
+		m.used++
+	}
+	m.slots[i] = hashSlot[K, V]{hash: hash, state: slotOccupied, key: key, val: val}
+	m.len++
+}
+
+// resize rehashes the entries into a new table, dropping deleted slots. The table
+// only grows if more than half of the used slots hold entries.
+func (m *HashMap[K, V]) resize() {
+	size := len(m.slots)
+	if size == 0 {
+		size = 8
+	} else if m.len*2 >= m.used {
+		size *= 2
+	}
+
+	old := m.slots
+	m.slots = make([]hashSlot[K, V], size)
+	m.used = m.len
+	mask := uint64(size - 1)
+	for _, slot := range old {
+		if slot.state != slotOccupied {
+			continue
+		}
+		i := slot.hash & mask
+		for m.slots[i].state != slotEmpty {
+			i = (i + 1) & mask
+		}
+		m.slots[i] = slot
+	}
+}
+
+// Delete removes the key, returning true if it existed.
+func (m *HashMap[K, V]) Delete(key K) bool {
+	i := m.find(key, m.hasher.Hash(key))
+	if i < 0 {
+		return false
+	}
+	// The slot is marked deleted rather than empty so probes for keys stored after
+	// it continue past it.
+	m.slots[i] = hashSlot[K, V]{state: slotDeleted}
+	m.len--
+	return true
+}
+
+// Len returns the number of entries.
+func (m *HashMap[K, V]) Len() int {
+	return m.len
+}
+
+// Clear removes all entries.
+func (m *HashMap[K, V]) Clear() {
+	m.slots = nil
+	m.len = 0
+	m.used = 0
+}
+
+// All returns an iterator over the entries of the map.
+//
+// The entries will be visited in an indeterminate order.
+func (m *HashMap[K, V]) All() func(yield func(key K, val V) bool) {
+	return func(yield func(key K, val V) bool) {
+		for i := range m.slots {
+			slot := &m.slots[i]
+			if slot.state == slotOccupied && !yield(slot.key, slot.val) {
+				return
+			}
+		}
+	}
+}
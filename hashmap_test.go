@@ -0,0 +1,141 @@
+package maps
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashMap(t *testing.T) {
+	m := NewHashMap[[]byte, int](BytesHasher())
+	m.Set([]byte("a"), 1)
+	m.Set([]byte("b"), 2)
+	m.Set([]byte("a"), 3)
+
+	val, ok := m.Get([]byte("a"))
+	assert.True(t, ok)
+	assert.Equal(t, 3, val)
+	_, ok = m.Get([]byte("c"))
+	assert.False(t, ok)
+	assert.True(t, m.Has([]byte("b")))
+	assert.Equal(t, 2, m.Len())
+
+	assert.True(t, m.Delete([]byte("a")))
+	assert.False(t, m.Delete([]byte("a")))
+	assert.False(t, m.Has([]byte("a")))
+	assert.Equal(t, 1, m.Len())
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	assert.False(t, m.Has([]byte("b")))
+	m.Set([]byte("b"), 4)
+	assert.Equal(t, []Entry[[]byte, int]{{Key: []byte("b"), Value: 4}}, EntriesOf[[]byte, int](m))
+}
+
+func TestHashMap_Collisions(t *testing.T) {
+	// A constant hash makes every key collide so lookups rely on probing and Equal.
+	hasher := Hasher[string]{
+		Hash:  func(key string) uint64 { return 42 },
+		Equal: func(a, b string) bool { return a == b },
+	}
+	m := NewHashMap[string, int](hasher)
+	for i := 0; i < 20; i++ {
+		m.Set(strconv.Itoa(i), i)
+	}
+	for i := 0; i < 20; i += 2 {
+		assert.True(t, m.Delete(strconv.Itoa(i)))
+	}
+	for i := 0; i < 20; i++ {
+		val, ok := m.Get(strconv.Itoa(i))
+		assert.Equal(t, i%2 == 1, ok, "key %d", i)
+		if ok {
+			assert.Equal(t, i, val)
+		}
+	}
+	assert.Equal(t, 10, m.Len())
+}
+
+func TestHashMap_MatchesBuiltinMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := NewHashMap[[]string, int](StringSliceHasher())
+	expected := make(map[string]int)
+
+	for i := 0; i < 10000; i++ {
+		n := rng.Intn(500)
+		key := []string{"k", strconv.Itoa(n)}
+		switch rng.Intn(3) {
+		case 0, 1:
+			m.Set(key, i)
+			expected[strconv.Itoa(n)] = i
+		case 2:
+			_, exists := expected[strconv.Itoa(n)]
+			assert.Equal(t, exists, m.Delete(key))
+			delete(expected, strconv.Itoa(n))
+		}
+	}
+
+	assert.Equal(t, len(expected), m.Len())
+	actual := make(map[string]int)
+	m.All()(func(key []string, val int) bool {
+		actual[key[1]] = val
+		return true
+	})
+	assert.Equal(t, expected, actual)
+}
+
+func TestBytesHasher(t *testing.T) {
+	h := BytesHasher()
+	assert.Equal(t, h.Hash([]byte("abc")), h.Hash([]byte("abc")))
+	assert.True(t, h.Equal([]byte("abc"), []byte("abc")))
+	assert.False(t, h.Equal([]byte("abc"), []byte("abd")))
+	assert.True(t, h.Equal(nil, []byte{}))
+}
+
+func TestStringSliceHasher(t *testing.T) {
+	h := StringSliceHasher()
+	assert.Equal(t, h.Hash([]string{"a", "b"}), h.Hash([]string{"a", "b"}))
+	assert.True(t, h.Equal([]string{"a", "b"}, []string{"a", "b"}))
+	assert.False(t, h.Equal([]string{"a", "b"}, []string{"a"}))
+
+	// Elements are length prefixed so moving characters between elements changes
+	// the hash.
+	assert.NotEqual(t, h.Hash([]string{"ab", "c"}), h.Hash([]string{"a", "bc"}))
+	assert.False(t, h.Equal([]string{"ab", "c"}, []string{"a", "bc"}))
+}
+
+func TestProjectionHasher(t *testing.T) {
+	type user struct {
+		Org     string
+		Name    string
+		Visits  int
+		Score   float64
+		Manager *user
+	}
+
+	byName := ProjectionHasher(func(u user) [2]string { return [2]string{u.Org, u.Name} })
+	a := user{Org: "a", Name: "b", Visits: 1}
+	b := user{Org: "a", Name: "b", Visits: 2}
+	assert.Equal(t, byName.Hash(a), byName.Hash(b))
+	assert.True(t, byName.Equal(a, b))
+	assert.False(t, byName.Equal(a, user{Org: "b", Name: "a"}))
+
+	manager := &user{Name: "m"}
+	byStruct := ProjectionHasher(func(u user) user { return user{Name: u.Name, Score: u.Score, Manager: u.Manager} })
+	a = user{Name: "b", Score: 0, Manager: manager}
+	b = user{Name: "b", Score: math.Copysign(0, -1), Manager: manager, Visits: 3}
+	assert.Equal(t, byStruct.Hash(a), byStruct.Hash(b))
+	assert.True(t, byStruct.Equal(a, b))
+
+	byValue := ProjectionHasher(func(v any) any { return v })
+	assert.Equal(t, byValue.Hash(1), byValue.Hash(1))
+	assert.False(t, byValue.Equal(1, int64(1)))
+
+	m := NewHashMap[user, int](byName)
+	m.Set(user{Org: "a", Name: "b", Visits: 1}, 1)
+	m.Set(user{Org: "a", Name: "b", Visits: 2}, 2)
+	assert.Equal(t, 1, m.Len())
+	assert.Equal(t, []user{{Org: "a", Name: "b", Visits: 1}}, KeysOf[user, int](m))
+}
@@ -0,0 +1,358 @@
+package maps
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Resolver resolves a reference that doesn't exist in the document being
+// interpolated, reporting whether it could be resolved.
+type Resolver func(ref string) (any, bool)
+
+// EnvResolver returns a Resolver looking up references as environment variables,
+// so "${HOME}" resolves to the value of $HOME unless the document has a key "HOME".
+func EnvResolver() Resolver {
+	return func(ref string) (any, bool) {
+		return os.LookupEnv(ref)
+	}
+}
+
+// InterpolateOptions configures Interpolate.
+type InterpolateOptions struct {
+	// Resolvers are consulted in order for references that don't exist in the
+	// document. Values returned by a Resolver are used as they are and aren't
+	// interpolated themselves.
+	Resolvers []Resolver
+	// IgnoreMissing leaves references that can't be resolved and have no fallback
+	// in place instead of failing.
+	IgnoreMissing bool
+}
+
+// InterpolationError is returned by Interpolate when a string value contains an
+// invalid reference or a reference that can't be resolved.
+type InterpolationError struct {
+	// Path is the JSON Pointer of the string value containing the reference.
+	Path string
+	// Ref is the reference, empty if the value couldn't be parsed.
+	Ref string
+	Err error
+}
+
+func (e *InterpolationError) Error() string {
+	if e.Ref == "" {
+		return fmt.Sprintf("interpolate %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("interpolate %s: reference %q: %v", e.Path, e.Ref, e.Err)
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+// CycleError is returned by Interpolate when values reference each other in a
+// cycle.
+type CycleError struct {
+	// Cycle holds the JSON Pointers of the values forming the cycle, starting and
+	// ending with the same value.
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "reference cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// Interpolate returns a copy of the nested document m with references of the form
+// "${path}" inside string values replaced by the value at the path, which is a path
+// as accepted by GetPath such as "${server.host}" or "${servers[0].port}". m is not
+// modified.
+//
+// References are resolved against the interpolated document, so referenced values
+// may contain references themselves. References that don't exist in the document
+// are passed to opts.Resolvers. If a reference can't be resolved, or resolves to nil
+// or an empty string, the fallback of a reference written as "${path:-fallback}" is
+// used instead. Fallbacks may contain references too. A literal "${" is written as
+// "$${".
+//
+// A string consisting of exactly one reference is replaced by the referenced value
+// itself, preserving its type, so "${server.port}" may resolve to an int or a map.
+// Otherwise the referenced values are formatted into the string, which fails for
+// maps and slices.
+//
+// A *CycleError is returned if values reference each other in a cycle and an
+// *InterpolationError if a reference is malformed or can't be resolved.
+func Interpolate(m map[string]any, opts InterpolateOptions) (map[string]any, error) {
+	doc, _ := deepCopyValue(m).(map[string]any)
+	in := interpolator{
+		opts:   opts,
+		doc:    doc,
+		active: make(map[string]int),
+		done:   make(map[string]bool),
+	}
+	if _, err := in.resolveAt(nil); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// interpolator resolves the values of a document in place. Values are resolved on
+// demand when they are referenced, active holds the values currently being resolved
+// and their position on the stack to detect cycles.
+type interpolator struct {
+	opts   InterpolateOptions
+	doc    map[string]any
+	stack  []string
+	active map[string]int
+	done   map[string]bool
+}
+
+// resolveAt resolves the value at tokens, including all values nested within it,
+// stores it in the document and returns it.
+func (in *interpolator) resolveAt(tokens []string) (any, error) {
+	ptr := pointerOf(tokens)
+	if pos, ok := in.active[ptr]; ok {
+		cycle := append(in.stack[pos:len(in.stack):len(in.stack)], ptr)
+		return nil, &CycleError{Cycle: cycle}
+	}
+	node := in.raw(tokens)
+	// Values within a resolved value, such as the keys of a map a reference resolved
+	// to, are resolved too and must not be interpolated again.
+	for i := len(tokens); i >= 0; i-- {
+		if in.done[pointerOf(tokens[:i])] {
+			return node, nil
+		}
+	}
+
+	in.active[ptr] = len(in.stack)
+	in.stack = append(in.stack, ptr)
+	defer func() {
+		in.stack = in.stack[:len(in.stack)-1]
+		delete(in.active, ptr)
+	}()
+
+	var err error
+	switch container := node.(type) {
+	case string:
+		if node, err = in.interpolate(ptr, container); err != nil {
+			return nil, err
+		}
+		in.store(tokens, node)
+	case map[string]any:
+		keys := make([]string, 0, len(container))
+		for k := range container {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if _, err := in.resolveAt(append(tokens[:len(tokens):len(tokens)], k)); err != nil {
+				return nil, err
+			}
+		}
+	case []any:
+		for i := range container {
+			if _, err := in.resolveAt(append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	in.done[ptr] = true
+	return node, nil
+}
+
+// raw returns the value at tokens without resolving it. The parents of the value
+// must have been resolved.
+func (in *interpolator) raw(tokens []string) any {
+	var node any = in.doc
+	for _, token := range tokens {
+		node, _ = interpolationChild(node, token)
+	}
+	return node
+}
+
+// store replaces the value at tokens.
+func (in *interpolator) store(tokens []string, val any) {
+	switch parent := in.raw(tokens[:len(tokens)-1]).(type) {
+	case map[string]any:
+		parent[tokens[len(tokens)-1]] = val
+	case []any:
+		idx, _ := strconv.Atoi(tokens[len(tokens)-1])
+		parent[idx] = val
+	}
+}
+
+// lookup resolves the value at tokens, resolving strings along the path since they
+// may resolve to maps or slices.
+func (in *interpolator) lookup(tokens []string) (any, bool, error) {
+	var node any = in.doc
+	var err error
+	for i, token := range tokens {
+		child, ok := interpolationChild(node, token)
+		if !ok {
+			return nil, false, nil
+		}
+		node = child
+		if _, isString := child.(string); isString || i == len(tokens)-1 {
+			if node, err = in.resolveAt(tokens[:i+1]); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	// The value is copied so the document doesn't share memory between values.
+	return deepCopyValue(node), true, nil
+}
+
+func interpolationChild(node any, token string) (any, bool) {
+	switch container := node.(type) {
+	case map[string]any:
+		val, ok := container[token]
+		return val, ok
+	case []any:
+		idx, err := pathIndex("", token, len(container), false)
+		if err != nil {
+			return nil, false
+		}
+		return container[idx], true
+	default:
+		return nil, false
+	}
+}
+
+// interpolate resolves the references in the string s at the JSON Pointer ptr.
+func (in *interpolator) interpolate(ptr string, s string) (any, error) {
+	parts, err := parseTemplate(s)
+	if err != nil {
+		return nil, &InterpolationError{Path: ptr, Err: err}
+	}
+	if len(parts) == 1 && parts[0].ref {
+		return in.resolveRef(ptr, parts[0])
+	}
+
+	var sb strings.Builder
+	for _, part := range parts {
+		if !part.ref {
+			sb.WriteString(part.text)
+			continue
+		}
+		val, err := in.resolveRef(ptr, part)
+		if err != nil {
+			return nil, err
+		}
+		str, err := formatInterpolated(val)
+		if err != nil {
+			return nil, &InterpolationError{Path: ptr, Ref: part.text, Err: err}
+		}
+		sb.WriteString(str)
+	}
+	return sb.String(), nil
+}
+
+func (in *interpolator) resolveRef(ptr string, part templatePart) (any, error) {
+	tokens, err := parsePath(part.text)
+	if err == nil && len(tokens) == 0 {
+		err = fmt.Errorf("%w: empty reference", ErrInvalidPath)
+	}
+	if err != nil {
+		return nil, &InterpolationError{Path: ptr, Ref: part.text, Err: err}
+	}
+
+	val, ok, err := in.lookup(tokens)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; !ok && i < len(in.opts.Resolvers); i++ {
+		val, ok = in.opts.Resolvers[i](part.text)
+	}
+
+	if part.fallback != nil && (!ok || val == nil || val == "") {
+		return in.interpolate(ptr, *part.fallback)
+	}
+	if !ok {
+		if in.opts.IgnoreMissing {
+			return part.source, nil
+		}
+		return nil, &InterpolationError{Path: ptr, Ref: part.text, Err: ErrPathNotFound}
+	}
+	return val, nil
+}
+
+// formatInterpolated formats a value embedded in a string.
+func formatInterpolated(val any) (string, error) {
+	if val == nil {
+		return "", nil
+	}
+	if s, ok := val.(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	s, err := toString(val, true)
+	if err != nil {
+		return "", fmt.Errorf("cannot embed %T in a string", val)
+	}
+	return s, nil
+}
+
+// templatePart is either literal text or a reference with an optional fallback.
+type templatePart struct {
+	text     string
+	ref      bool
+	fallback *string
+	// source is the reference as written, including its delimiters.
+	source string
+}
+
+// parseTemplate splits s into literal text and references.
+func parseTemplate(s string) ([]templatePart, error) {
+	var parts []templatePart
+	var literal strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			literal.WriteString("${")
+			i += 3
+		case strings.HasPrefix(s[i:], "${"):
+			end, err := referenceEnd(s, i)
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				parts = append(parts, templatePart{text: literal.String()})
+				literal.Reset()
+			}
+			part := templatePart{ref: true, source: s[i : end+1]}
+			name, fallback, hasFallback := strings.Cut(s[i+2:end], ":-")
+			part.text = name
+			if hasFallback {
+				part.fallback = &fallback
+			}
+			parts = append(parts, part)
+			i = end + 1
+		default:
+			literal.WriteByte(s[i])
+			i++
+		}
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, templatePart{text: literal.String()})
+	}
+	return parts, nil
+}
+
+// referenceEnd returns the index of the brace closing the reference starting at
+// start, skipping over references nested in a fallback.
+func referenceEnd(s string, start int) (int, error) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated reference at offset %d", start)
+}
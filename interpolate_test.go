@@ -0,0 +1,213 @@
+package maps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      map[string]any
+		opts     InterpolateOptions
+		expected map[string]any
+	}{
+		{
+			name: "String References",
+			doc: map[string]any{
+				"server": map[string]any{"host": "localhost", "port": 8080},
+				"url":    "http://${server.host}:${server.port}/",
+			},
+			expected: map[string]any{
+				"server": map[string]any{"host": "localhost", "port": 8080},
+				"url":    "http://localhost:8080/",
+			},
+		},
+		{
+			name: "Typed References",
+			doc: map[string]any{
+				"server":  map[string]any{"port": 8080, "tls": true},
+				"port":    "${server.port}",
+				"tls":     "${/server/tls}",
+				"backup":  "${server}",
+				"servers": []any{"${server}"},
+				"first":   "${servers[0].port}",
+			},
+			expected: map[string]any{
+				"server":  map[string]any{"port": 8080, "tls": true},
+				"port":    8080,
+				"tls":     true,
+				"backup":  map[string]any{"port": 8080, "tls": true},
+				"servers": []any{map[string]any{"port": 8080, "tls": true}},
+				"first":   8080,
+			},
+		},
+		{
+			name: "Chained References",
+			doc: map[string]any{
+				"base":  "http://${host}",
+				"api":   "${base}/api",
+				"host":  "${name}.example.com",
+				"name":  "app",
+				"alias": "${target}",
+				"url":   "${alias.url}",
+				"target": map[string]any{
+					"url": "${api}/v1",
+				},
+			},
+			expected: map[string]any{
+				"base":   "http://app.example.com",
+				"api":    "http://app.example.com/api",
+				"host":   "app.example.com",
+				"name":   "app",
+				"alias":  map[string]any{"url": "http://app.example.com/api/v1"},
+				"url":    "http://app.example.com/api/v1",
+				"target": map[string]any{"url": "http://app.example.com/api/v1"},
+			},
+		},
+		{
+			name: "Fallbacks",
+			doc: map[string]any{
+				"empty":  "",
+				"port":   "${server.port:-8080}",
+				"host":   "${empty:-localhost}",
+				"nested": "${missing:-${other:-${port}}}",
+				"blank":  "[${missing:-}]",
+			},
+			expected: map[string]any{
+				"empty":  "",
+				"port":   "8080",
+				"host":   "localhost",
+				"nested": "8080",
+				"blank":  "[]",
+			},
+		},
+		{
+			name: "Escapes",
+			doc: map[string]any{
+				"literal": "$${name} costs $5",
+				"copy":    "${literal}",
+				"nested":  "${missing:-$${x}}",
+			},
+			expected: map[string]any{
+				"literal": "${name} costs $5",
+				"copy":    "${name} costs $5",
+				"nested":  "${x}",
+			},
+		},
+		{
+			name: "Resolvers",
+			doc: map[string]any{
+				"home":  "${HOME}/app",
+				"local": "${secret}",
+				"user":  "${USER:-nobody}",
+			},
+			opts: InterpolateOptions{
+				Resolvers: []Resolver{
+					func(ref string) (any, bool) {
+						if ref == "secret" {
+							return "${not interpolated}", true
+						}
+						return nil, false
+					},
+					EnvResolver(),
+				},
+			},
+			expected: map[string]any{
+				"home":  "/home/test/app",
+				"local": "${not interpolated}",
+				"user":  "nobody",
+			},
+		},
+		{
+			name: "Ignore Missing",
+			doc: map[string]any{
+				"a": "${missing}",
+				"b": "x-${missing}-${a}",
+			},
+			opts: InterpolateOptions{IgnoreMissing: true},
+			expected: map[string]any{
+				"a": "${missing}",
+				"b": "x-${missing}-${missing}",
+			},
+		},
+	}
+
+	t.Setenv("HOME", "/home/test")
+	t.Setenv("USER", "")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := deepCopyValue(test.doc)
+			actual, err := Interpolate(test.doc, test.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, original, test.doc)
+		})
+	}
+}
+
+func TestInterpolate_CopiesReferencedValues(t *testing.T) {
+	actual, err := Interpolate(map[string]any{
+		"a": map[string]any{"b": []any{1}},
+		"c": "${a}",
+	}, InterpolateOptions{})
+	assert.NoError(t, err)
+
+	actual["c"].(map[string]any)["b"].([]any)[0] = 2
+	assert.Equal(t, []any{1}, actual["a"].(map[string]any)["b"])
+}
+
+func TestInterpolate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      map[string]any
+		expected error
+	}{
+		{
+			name:     "Missing Reference",
+			doc:      map[string]any{"server": map[string]any{"url": "http://${host}"}},
+			expected: &InterpolationError{Path: "/server/url", Ref: "host", Err: ErrPathNotFound},
+		},
+		{
+			name:     "Unterminated Reference",
+			doc:      map[string]any{"a": "x ${b"},
+			expected: &InterpolationError{Path: "/a", Err: errors.New("unterminated reference at offset 2")},
+		},
+		{
+			name:     "Not Embeddable",
+			doc:      map[string]any{"a": []any{1}, "b": "x${a}"},
+			expected: &InterpolationError{Path: "/b", Ref: "a", Err: errors.New("cannot embed []interface {} in a string")},
+		},
+		{
+			name:     "Self Reference",
+			doc:      map[string]any{"a": "${a}"},
+			expected: &CycleError{Cycle: []string{"/a", "/a"}},
+		},
+		{
+			name:     "Cycle",
+			doc:      map[string]any{"a": "${b}", "b": "x${c.d}", "c": map[string]any{"d": "${a:-fallback}"}},
+			expected: &CycleError{Cycle: []string{"/a", "/b", "/c/d", "/a"}},
+		},
+		{
+			name:     "Reference To Ancestor",
+			doc:      map[string]any{"a": map[string]any{"b": []any{"${a}"}}},
+			expected: &CycleError{Cycle: []string{"/a", "/a/b", "/a/b/0", "/a"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Interpolate(test.doc, InterpolateOptions{})
+			assert.Equal(t, test.expected, err)
+		})
+	}
+
+	_, err := Interpolate(map[string]any{"a": "${b}", "b": "${a}"}, InterpolateOptions{})
+	assert.EqualError(t, err, "reference cycle: /a -> /b -> /a")
+
+	_, err = Interpolate(map[string]any{"a": "${b[0}"}, InterpolateOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	assert.EqualError(t, err, `interpolate /a: reference "b[0": path "b[0": segment "b[0": invalid path: malformed index`)
+}
@@ -0,0 +1,384 @@
+package maps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch operation names as defined by RFC 6902.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// ErrPatchTestFailed is returned, wrapped in a *JSONPatchError, when a test
+// operation finds a value that doesn't match the expected value.
+var ErrPatchTestFailed = errors.New("test failed")
+
+// Operation is a single JSON Patch (RFC 6902) operation. Path and From are JSON
+// Pointers (RFC 6901).
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The value member is always emitted for
+// add, replace and test operations, even if it is null, and never for the others.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case OpAdd, OpReplace, OpTest:
+		return json.Marshal(struct {
+			Op    string `json:"op"`
+			Path  string `json:"path"`
+			Value any    `json:"value"`
+		}{o.Op, o.Path, o.Value})
+	default:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+			From string `json:"from,omitempty"`
+		}{o.Op, o.Path, o.From})
+	}
+}
+
+// JSONPatchError is returned by ApplyJSONPatch when an operation cannot be applied.
+type JSONPatchError struct {
+	// Index is the position of the failing operation in the patch.
+	Index int
+	Op    Operation
+	Err   error
+}
+
+func (e *JSONPatchError) Error() string {
+	return fmt.Sprintf("json patch operation %d (%s %q): %v", e.Index, e.Op.Op, e.Op.Path, e.Err)
+}
+
+func (e *JSONPatchError) Unwrap() error {
+	return e.Err
+}
+
+// CreateJSONPatch returns the JSON Patch (RFC 6902) operations that transform the
+// before document into the after document. Nested maps are diffed key by key in
+// sorted order, slices are diffed index by index with elements added or removed
+// at the end, and all other values are replaced.
+//
+// The values in the returned operations are copies and don't share memory with
+// the after document.
+func CreateJSONPatch(before, after map[string]any) []Operation {
+	ops := make([]Operation, 0)
+	createPatchObject("", before, after, &ops)
+	return ops
+}
+
+func createPatchValue(path string, before, after any, ops *[]Operation) {
+	switch b := before.(type) {
+	case map[string]any:
+		if a, ok := after.(map[string]any); ok {
+			createPatchObject(path, b, a, ops)
+			return
+		}
+	case []any:
+		if a, ok := after.([]any); ok {
+			createPatchArray(path, b, a, ops)
+			return
+		}
+	}
+	if !jsonEqual(before, after) {
+		*ops = append(*ops, Operation{Op: OpReplace, Path: path, Value: deepCopyValue(after)})
+	}
+}
+
+func createPatchObject(path string, before, after map[string]any, ops *[]Operation) {
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := joinPointer(path, k)
+		bv, bok := before[k]
+		av, aok := after[k]
+		switch {
+		case bok && aok:
+			createPatchValue(childPath, bv, av, ops)
+		case bok:
+			*ops = append(*ops, Operation{Op: OpRemove, Path: childPath})
+		default:
+			*ops = append(*ops, Operation{Op: OpAdd, Path: childPath, Value: deepCopyValue(av)})
+		}
+	}
+}
+
+func createPatchArray(path string, before, after []any, ops *[]Operation) {
+	common := len(before)
+	if len(after) < common {
+		common = len(after)
+	}
+	for i := 0; i < common; i++ {
+		createPatchValue(joinPointer(path, strconv.Itoa(i)), before[i], after[i], ops)
+	}
+	for i := common; i < len(after); i++ {
+		*ops = append(*ops, Operation{Op: OpAdd, Path: joinPointer(path, strconv.Itoa(i)), Value: deepCopyValue(after[i])})
+	}
+	// Remove from the end so the indexes of the remaining elements don't shift.
+	for i := len(before) - 1; i >= common; i-- {
+		*ops = append(*ops, Operation{Op: OpRemove, Path: joinPointer(path, strconv.Itoa(i))})
+	}
+}
+
+// ApplyJSONPatch applies the JSON Patch (RFC 6902) operations to a copy of doc and
+// returns the patched copy. The add, remove, replace, move, copy and test
+// operations are supported.
+//
+// The patch is applied atomically: if any operation fails a *JSONPatchError is
+// returned along with a nil map. doc is never modified.
+func ApplyJSONPatch(doc map[string]any, ops []Operation) (map[string]any, error) {
+	// A nil document is patched like an empty one, since a nil map can't be added to.
+	var root any = deepCopyDocument(doc)
+	if doc == nil {
+		root = make(map[string]any)
+	}
+	for i, op := range ops {
+		var err error
+		root, err = applyOperation(root, op)
+		if err != nil {
+			return nil, &JSONPatchError{Index: i, Op: op, Err: err}
+		}
+	}
+
+	res, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("json patch replaced the document with a non-object value of type %T", root)
+	}
+	return res, nil
+}
+
+func applyOperation(root any, op Operation) (any, error) {
+	if op.Path != "" && !strings.HasPrefix(op.Path, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", op.Path)
+	}
+	path := splitPointer(op.Path)
+
+	switch op.Op {
+	case OpAdd:
+		return patchAdd(root, path, deepCopyValue(op.Value))
+	case OpRemove:
+		return patchRemove(root, path)
+	case OpReplace:
+		return patchReplace(root, path, deepCopyValue(op.Value))
+	case OpMove:
+		from, err := fromPointer(op)
+		if err != nil {
+			return nil, err
+		}
+		if op.From == op.Path {
+			return root, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %q into one of its children", op.From)
+		}
+		val, err := patchGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		root, err = patchRemove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(root, path, val)
+	case OpCopy:
+		from, err := fromPointer(op)
+		if err != nil {
+			return nil, err
+		}
+		val, err := patchGet(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(root, path, deepCopyValue(val))
+	case OpTest:
+		val, err := patchGet(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(val, op.Value) {
+			return nil, ErrPatchTestFailed
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+}
+
+func fromPointer(op Operation) ([]string, error) {
+	if op.From != "" && !strings.HasPrefix(op.From, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", op.From)
+	}
+	return splitPointer(op.From), nil
+}
+
+func patchGet(node any, path []string) (any, error) {
+	for i, token := range path {
+		switch container := node.(type) {
+		case map[string]any:
+			val, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", pointerOf(path[:i+1]))
+			}
+			node = val
+		case []any:
+			idx, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %w", pointerOf(path[:i+1]), err)
+			}
+			node = container[idx]
+		default:
+			return nil, fmt.Errorf("path %q traverses a value of type %T", pointerOf(path[:i+1]), node)
+		}
+	}
+	return node, nil
+}
+
+// patchUpdate walks to the parent of the location referenced by path and replaces
+// the parent with the result of fn, returning the possibly new root.
+func patchUpdate(node any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	token := path[0]
+	switch container := node.(type) {
+	case map[string]any:
+		child, ok := container[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q does not exist", token)
+		}
+		updated, err := patchUpdate(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []any:
+		idx, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := patchUpdate(container[idx], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[idx] = updated
+		return container, nil
+	default:
+		return nil, fmt.Errorf("cannot traverse %q in a value of type %T", token, node)
+	}
+}
+
+func patchAdd(root any, path []string, val any) (any, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+	return patchUpdate(root, path, func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if container == nil {
+				container = make(map[string]any)
+			}
+			container[token] = val
+			return container, nil
+		case []any:
+			idx := len(container)
+			if token != "-" {
+				var err error
+				if idx, err = arrayIndex(token, len(container)); err != nil {
+					return nil, err
+				}
+			}
+			container = append(container, nil)
+			copy(container[idx+1:], container[idx:])
+			container[idx] = val
+			return container, nil
+		default:
+			return nil, fmt.Errorf("cannot add %q to a value of type %T", token, parent)
+		}
+	})
+}
+
+func patchRemove(root any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the document root")
+	}
+	return patchUpdate(root, path, func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if _, ok := container[token]; !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			delete(container, token)
+			return container, nil
+		case []any:
+			idx, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:idx:idx], container[idx+1:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot remove %q from a value of type %T", token, parent)
+		}
+	})
+}
+
+func patchReplace(root any, path []string, val any) (any, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+	return patchUpdate(root, path, func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if _, ok := container[token]; !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			container[token] = val
+			return container, nil
+		case []any:
+			idx, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			container[idx] = val
+			return container, nil
+		default:
+			return nil, fmt.Errorf("cannot replace %q in a value of type %T", token, parent)
+		}
+	})
+}
+
+// arrayIndex parses an array index reference token per RFC 6901, which forbids
+// leading zeros, and checks it is no greater than max.
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx > max {
+		return 0, fmt.Errorf("array index %s out of range", token)
+	}
+	return idx, nil
+}
@@ -0,0 +1,292 @@
+package maps
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeJSON[T any](t *testing.T, s string) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decoding %s: %v", s, err)
+	}
+	return v
+}
+
+// TestApplyJSONPatch_RFC6902 runs the examples from Appendix A of RFC 6902.
+func TestApplyJSONPatch_RFC6902(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "A.1 Adding an Object Member",
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			expected: `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:     "A.2 Adding an Array Element",
+			doc:      `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			expected: `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:     "A.3 Removing an Object Member",
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			expected: `{"foo": "bar"}`,
+		},
+		{
+			name:     "A.4 Removing an Array Element",
+			doc:      `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			expected: `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:     "A.5 Replacing a Value",
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			expected: `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:     "A.6 Moving a Value",
+			doc:      `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:     "A.7 Moving an Array Element",
+			doc:      `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			expected: `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "A.8 Testing a Value: Success",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:  "A.9 Testing a Value: Error",
+			doc:   `{"baz": "qux"}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			err:   true,
+		},
+		{
+			name:     "A.10 Adding a Nested Member Object",
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			expected: `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:     "A.11 Ignoring Unrecognized Elements",
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			expected: `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:  "A.12 Adding to a Nonexistent Target",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			err:   true,
+		},
+		{
+			name:     "A.14 ~ Escape Ordering",
+			doc:      `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": 10}]`,
+			expected: `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:  "A.15 Comparing Strings and Numbers",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			err:   true,
+		},
+		{
+			name:     "A.16 Adding an Array Value",
+			doc:      `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			expected: `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := decodeJSON[map[string]any](t, test.doc)
+			ops := decodeJSON[[]Operation](t, test.patch)
+
+			actual, err := ApplyJSONPatch(doc, ops)
+			if test.err {
+				var patchErr *JSONPatchError
+				assert.ErrorAs(t, err, &patchErr)
+				assert.Nil(t, actual)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, decodeJSON[map[string]any](t, test.expected), actual)
+		})
+	}
+}
+
+func TestApplyJSONPatch_Atomic(t *testing.T) {
+	doc := map[string]any{
+		"name": "api",
+		"tags": []any{"a", "b"},
+	}
+	ops := []Operation{
+		{Op: OpReplace, Path: "/name", Value: "web"},
+		{Op: OpRemove, Path: "/tags/0"},
+		{Op: OpTest, Path: "/name", Value: "api"},
+	}
+
+	actual, err := ApplyJSONPatch(doc, ops)
+	assert.ErrorIs(t, err, ErrPatchTestFailed)
+	assert.Equal(t, 2, err.(*JSONPatchError).Index)
+	assert.Nil(t, actual)
+	assert.Equal(t, map[string]any{"name": "api", "tags": []any{"a", "b"}}, doc)
+}
+
+func TestApplyJSONPatch_Errors(t *testing.T) {
+	doc := map[string]any{"a": map[string]any{"b": 1}, "list": []any{1, 2}}
+
+	tests := []struct {
+		name string
+		op   Operation
+	}{
+		{name: "Unsupported Operation", op: Operation{Op: "merge", Path: "/a"}},
+		{name: "Invalid Pointer", op: Operation{Op: OpAdd, Path: "a"}},
+		{name: "Remove Missing Member", op: Operation{Op: OpRemove, Path: "/missing"}},
+		{name: "Replace Missing Member", op: Operation{Op: OpReplace, Path: "/missing", Value: 1}},
+		{name: "Index Out Of Range", op: Operation{Op: OpAdd, Path: "/list/3", Value: 1}},
+		{name: "Leading Zero Index", op: Operation{Op: OpRemove, Path: "/list/01"}},
+		{name: "Move Into Child", op: Operation{Op: OpMove, From: "/a", Path: "/a/c"}},
+		{name: "Copy Missing Source", op: Operation{Op: OpCopy, From: "/missing", Path: "/b"}},
+		{name: "Remove Root", op: Operation{Op: OpRemove, Path: ""}},
+		{name: "Replace Root With Scalar", op: Operation{Op: OpReplace, Path: "", Value: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ApplyJSONPatch(doc, []Operation{test.op})
+			assert.Error(t, err)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func TestApplyJSONPatch_CopyIsDeep(t *testing.T) {
+	doc := map[string]any{"a": map[string]any{"b": 1}}
+	actual, err := ApplyJSONPatch(doc, []Operation{
+		{Op: OpCopy, From: "/a", Path: "/c"},
+		{Op: OpReplace, Path: "/c/b", Value: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"a": map[string]any{"b": 1},
+		"c": map[string]any{"b": 2},
+	}, actual)
+}
+
+func TestApplyJSONPatch_NilDocument(t *testing.T) {
+	actual, err := ApplyJSONPatch(nil, []Operation{{Op: OpAdd, Path: "/a", Value: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": 1}, actual)
+
+	actual, err = ApplyJSONPatch(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{}, actual)
+
+	actual, err = ApplyJSONPatch(map[string]any{"a": map[string]any(nil)}, []Operation{{Op: OpAdd, Path: "/a/b", Value: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": map[string]any{"b": 1}}, actual)
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	before := map[string]any{
+		"name":   "api",
+		"remove": true,
+		"server": map[string]any{"host": "localhost", "port": 8080},
+		"tags":   []any{"a", "b", "c"},
+		"hosts":  []any{"x"},
+		"type":   map[string]any{"nested": 1},
+	}
+	after := map[string]any{
+		"name":   "web",
+		"server": map[string]any{"host": "localhost", "port": 9090, "tls": true},
+		"tags":   []any{"a", "z"},
+		"hosts":  []any{"x", "y"},
+		"type":   "flat",
+		"added":  nil,
+	}
+
+	ops := CreateJSONPatch(before, after)
+	assert.Equal(t, []Operation{
+		{Op: OpAdd, Path: "/added", Value: nil},
+		{Op: OpAdd, Path: "/hosts/1", Value: "y"},
+		{Op: OpReplace, Path: "/name", Value: "web"},
+		{Op: OpRemove, Path: "/remove"},
+		{Op: OpReplace, Path: "/server/port", Value: 9090},
+		{Op: OpAdd, Path: "/server/tls", Value: true},
+		{Op: OpReplace, Path: "/tags/1", Value: "z"},
+		{Op: OpRemove, Path: "/tags/2"},
+		{Op: OpReplace, Path: "/type", Value: "flat"},
+	}, ops)
+
+	actual, err := ApplyJSONPatch(before, ops)
+	assert.NoError(t, err)
+	assert.Equal(t, after, actual)
+
+	assert.Empty(t, CreateJSONPatch(after, after))
+}
+
+// TestCreateJSONPatch_RoundTrip creates patches between the documents of the RFC 6902
+// examples and checks applying them produces the expected document.
+func TestCreateJSONPatch_RoundTrip(t *testing.T) {
+	docs := [][2]string{
+		{`{"foo": "bar"}`, `{"baz": "qux", "foo": "bar"}`},
+		{`{"foo": ["bar", "baz"]}`, `{"foo": ["bar", "qux", "baz"]}`},
+		{`{"foo": ["bar", "qux", "baz"]}`, `{"foo": ["bar", "baz"]}`},
+		{`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`},
+		{`{"foo": ["all", "grass", "cows", "eat"]}`, `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{`{"foo": "bar"}`, `{"foo": "bar", "child": {"grandchild": {}}}`},
+		{`{"/": 9, "~1": 10}`, `{"/": 9, "~1": 11, "a/b~c": [1]}`},
+		{`{"foo": ["bar"]}`, `{"foo": ["bar", ["abc", "def"]]}`},
+	}
+
+	for _, pair := range docs {
+		before := decodeJSON[map[string]any](t, pair[0])
+		after := decodeJSON[map[string]any](t, pair[1])
+
+		ops := CreateJSONPatch(before, after)
+		encoded, err := json.Marshal(ops)
+		assert.NoError(t, err)
+
+		actual, err := ApplyJSONPatch(before, decodeJSON[[]Operation](t, string(encoded)))
+		assert.NoError(t, err)
+		assert.Equal(t, after, actual)
+	}
+}
+
+func TestOperation_MarshalJSON(t *testing.T) {
+	encoded, err := json.Marshal([]Operation{
+		{Op: OpAdd, Path: "/a", Value: nil},
+		{Op: OpRemove, Path: "/b"},
+		{Op: OpMove, From: "/c", Path: "/d"},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "add", "path": "/a", "value": null},
+		{"op": "remove", "path": "/b"},
+		{"op": "move", "from": "/c", "path": "/d"}
+	]`, string(encoded))
+}
@@ -0,0 +1,171 @@
+package maps
+
+// Map is the interface implemented by map types such as HashMap, NormalizedMap and
+// the adapters GoMap and SyncMap. The functions suffixed with Of, such as KeysOf and
+// DiffOf, and the functions suffixed with Into work on any Map.
+type Map[K any, V any] interface {
+	// Get returns the value of the key and whether it exists.
+	Get(key K) (V, bool)
+	// Set sets the value of the key.
+	Set(key K, val V)
+	// Delete removes the key, returning true if it existed.
+	Delete(key K) bool
+	// Len returns the number of entries.
+	Len() int
+	// All returns an iterator calling yield for every entry until yield returns
+	// false. The entries are visited in an indeterminate order and must not be
+	// added or removed while iterating.
+	All() func(yield func(key K, val V) bool)
+}
+
+// KeysOf returns all the keys in the provided Map.
+//
+// The keys will be in an indeterminate order.
+func KeysOf[K, V any](m Map[K, V]) []K {
+	if gm, ok := m.(goMap[K, V]); ok {
+		return gm.keys()
+	}
+	keys := make([]K, 0, m.Len())
+	m.All()(func(key K, val V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// ValuesOf returns all the values in the provided Map.
+//
+// The values will be in an indeterminate order.
+func ValuesOf[K, V any](m Map[K, V]) []V {
+	if gm, ok := m.(goMap[K, V]); ok {
+		return gm.values()
+	}
+	vals := make([]V, 0, m.Len())
+	m.All()(func(key K, val V) bool {
+		vals = append(vals, val)
+		return true
+	})
+	return vals
+}
+
+// EntriesOf returns all entries in the provided Map as a slice of Entry.
+//
+// The results will be in an indeterminate order.
+func EntriesOf[K, V any](m Map[K, V]) []Entry[K, V] {
+	if gm, ok := m.(goMap[K, V]); ok {
+		return gm.entries()
+	}
+	res := make([]Entry[K, V], 0, m.Len())
+	m.All()(func(key K, val V) bool {
+		res = append(res, Entry[K, V]{Key: key, Value: val})
+		return true
+	})
+	return res
+}
+
+// FilterInto sets the entries of src that satisfy the predicate in dst, which is
+// usually a new empty Map.
+func FilterInto[K, V any](dst Map[K, V], src Map[K, V], fn Predicate[K, V]) {
+	if gm, ok := src.(goMap[K, V]); ok && gm.filterInto(dst, fn) {
+		return
+	}
+	src.All()(func(key K, val V) bool {
+		if fn(key, val) {
+			dst.Set(key, val)
+		}
+		return true
+	})
+}
+
+// MergeInto merges the entries of the source maps into dst, which is usually a new
+// empty Map. If a key already exists in dst, or in multiple maps, the
+// ConflictResolver is called to resolve the conflict like Merge.
+func MergeInto[K, V any](dst Map[K, V], fn ConflictResolver[V], src ...Map[K, V]) {
+	gm, isGoMap := dst.(goMap[K, V])
+	for _, m := range src {
+		if isGoMap && gm.mergeFrom(m, fn) {
+			continue
+		}
+		m.All()(func(key K, val V) bool {
+			if existing, ok := dst.Get(key); ok {
+				val = fn(existing, val)
+			}
+			dst.Set(key, val)
+			return true
+		})
+	}
+}
+
+// EntryDiff describes how the entry of a key differs between two maps.
+type EntryDiff[K any, V comparable] struct {
+	Key K
+	EntryComparison[V]
+}
+
+// DiffOf compares two maps like Diff and returns the entries that differ. Entries
+// differing in value or missing from the right map are returned first, in the
+// iteration order of the left map, followed by the entries missing from the left map.
+func DiffOf[K any, V comparable](left Map[K, V], right Map[K, V]) []EntryDiff[K, V] {
+	var res []EntryDiff[K, V]
+	if gm, ok := left.(goMap[K, V]); ok {
+		add := func(key K, l, r V, reason DiffReason) {
+			res = append(res, EntryDiff[K, V]{
+				Key:             key,
+				EntryComparison: EntryComparison[V]{Left: l, Right: r, Reason: reason},
+			})
+		}
+		if gm.diff(right, equalComparable[V], add) {
+			return res
+		}
+	}
+
+	left.All()(func(key K, val V) bool {
+		otherVal, ok := right.Get(key)
+		if !ok {
+			res = append(res, EntryDiff[K, V]{
+				Key:             key,
+				EntryComparison: EntryComparison[V]{Left: val, Reason: DiffMissingRight},
+			})
+		} else if val != otherVal {
+			res = append(res, EntryDiff[K, V]{
+				Key:             key,
+				EntryComparison: EntryComparison[V]{Left: val, Right: otherVal, Reason: DiffValue},
+			})
+		}
+		return true
+	})
+	right.All()(func(key K, val V) bool {
+		if _, ok := left.Get(key); !ok {
+			res = append(res, EntryDiff[K, V]{
+				Key:             key,
+				EntryComparison: EntryComparison[V]{Right: val, Reason: DiffMissingLeft},
+			})
+		}
+		return true
+	})
+	return res
+}
+
+// EqualOf compares two maps like Equal and returns a boolean value indicating if
+// they hold the same keys with equal values.
+func EqualOf[K any, V comparable](m1, m2 Map[K, V]) bool {
+	if gm, ok := m1.(goMap[K, V]); ok {
+		if equal, ok := gm.equal(m2, equalComparable[V]); ok {
+			return equal
+		}
+	}
+	if m1.Len() != m2.Len() {
+		return false
+	}
+	equal := true
+	m1.All()(func(key K, val V) bool {
+		other, ok := m2.Get(key)
+		equal = ok && val == other
+		return equal
+	})
+	return equal
+}
+
+func equalComparable[V comparable](a, b V) bool {
+	return a == b
+}
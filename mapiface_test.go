@@ -0,0 +1,200 @@
+package maps
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      map[string]int
+		keys    []string
+		values  []int
+		entries []Entry[[]byte, int]
+	}{
+		{
+			name:   "Entries",
+			in:     map[string]int{"a": 1, "b": 2, "c": 3},
+			keys:   []string{"a", "b", "c"},
+			values: []int{1, 2, 3},
+			entries: []Entry[[]byte, int]{
+				{Key: []byte("a"), Value: 1},
+				{Key: []byte("b"), Value: 2},
+				{Key: []byte("c"), Value: 3},
+			},
+		},
+		{
+			name:    "Empty",
+			in:      map[string]int{},
+			keys:    []string{},
+			values:  []int{},
+			entries: []Entry[[]byte, int]{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// []byte keys aren't comparable, so the functions have to use the Map
+			// interface.
+			m := NewHashMap[[]byte, int](BytesHasher())
+			for k, v := range test.in {
+				m.Set([]byte(k), v)
+			}
+
+			keys := make([]string, 0)
+			for _, key := range KeysOf[[]byte, int](m) {
+				keys = append(keys, string(key))
+			}
+			sort.Strings(keys)
+			assert.Equal(t, test.keys, keys)
+
+			vals := ValuesOf[[]byte, int](m)
+			sort.Ints(vals)
+			assert.Equal(t, test.values, vals)
+
+			entries := EntriesOf[[]byte, int](m)
+			sort.Slice(entries, func(i, j int) bool {
+				return string(entries[i].Key) < string(entries[j].Key)
+			})
+			assert.Equal(t, test.entries, entries)
+		})
+	}
+}
+
+func TestFilterInto(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       map[string]int
+		expected map[string]int
+	}{
+		{
+			name:     "Matching Entries",
+			in:       map[string]int{"apple": 1, "avocado": 2, "banana": 3},
+			expected: map[string]int{"apple": 1, "avocado": 2},
+		},
+		{
+			name:     "No Matching Entries",
+			in:       map[string]int{"banana": 3},
+			expected: map[string]int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := NewHashMap[[]byte, int](BytesHasher())
+			for k, v := range test.in {
+				src.Set([]byte(k), v)
+			}
+			dst := NewHashMap[[]byte, int](BytesHasher())
+			FilterInto[[]byte, int](dst, src, func(key []byte, val int) bool {
+				return bytes.HasPrefix(key, []byte("a"))
+			})
+
+			actual := make(map[string]int)
+			dst.All()(func(key []byte, val int) bool {
+				actual[string(key)] = val
+				return true
+			})
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, len(test.in), src.Len())
+		})
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	tests := []struct {
+		name     string
+		dst      map[string]int
+		src      []map[string]int
+		expected map[string]int
+	}{
+		{
+			name: "Conflicts Resolved",
+			dst:  map[string]int{"a": 1},
+			src: []map[string]int{
+				{"a": 2, "b": 3},
+				{"b": 4, "c": 5},
+			},
+			expected: map[string]int{"a": 3, "b": 7, "c": 5},
+		},
+		{
+			name:     "No Sources",
+			dst:      map[string]int{"a": 1},
+			expected: map[string]int{"a": 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := NewHashMap[[]byte, int](BytesHasher())
+			for k, v := range test.dst {
+				dst.Set([]byte(k), v)
+			}
+			var src []Map[[]byte, int]
+			for _, entries := range test.src {
+				m := NewHashMap[[]byte, int](BytesHasher())
+				for k, v := range entries {
+					m.Set([]byte(k), v)
+				}
+				src = append(src, m)
+			}
+			MergeInto[[]byte, int](dst, SumResolver[int](), src...)
+
+			actual := make(map[string]int)
+			dst.All()(func(key []byte, val int) bool {
+				actual[string(key)] = val
+				return true
+			})
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDiffOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     map[string]int
+		right    map[string]int
+		expected map[string]EntryComparison[int]
+	}{
+		{
+			name:  "Differences",
+			left:  map[string]int{"same": 1, "changed": 2, "removed": 3},
+			right: map[string]int{"same": 1, "changed": 4, "added": 5},
+			expected: map[string]EntryComparison[int]{
+				"changed": {Left: 2, Right: 4, Reason: DiffValue},
+				"removed": {Left: 3, Reason: DiffMissingRight},
+				"added":   {Right: 5, Reason: DiffMissingLeft},
+			},
+		},
+		{
+			name:     "Equal",
+			left:     map[string]int{"same": 1},
+			right:    map[string]int{"same": 1},
+			expected: map[string]EntryComparison[int]{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			left := NewHashMap[[]byte, int](BytesHasher())
+			for k, v := range test.left {
+				left.Set([]byte(k), v)
+			}
+			right := NewHashMap[[]byte, int](BytesHasher())
+			for k, v := range test.right {
+				right.Set([]byte(k), v)
+			}
+
+			diff := make(map[string]EntryComparison[int])
+			for _, entry := range DiffOf[[]byte, int](left, right) {
+				diff[string(entry.Key)] = entry.EntryComparison
+			}
+			assert.Equal(t, test.expected, diff)
+		})
+	}
+}
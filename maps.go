@@ -1,7 +1,11 @@
 package maps
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -25,6 +29,124 @@ func NopResolver[V any]() ConflictResolver[V] {
 	}
 }
 
+// Number is a constraint that permits any integer or floating point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Ordered is a constraint that permits any type supporting the operators < <= >= >.
+type Ordered interface {
+	Number | ~string
+}
+
+// SumResolver returns a ConflictResolver that adds the values together.
+func SumResolver[V Number]() ConflictResolver[V] {
+	return func(left, right V) V {
+		return left + right
+	}
+}
+
+// MinResolver returns a ConflictResolver that keeps the smaller value.
+func MinResolver[V Ordered]() ConflictResolver[V] {
+	return func(left, right V) V {
+		if right < left {
+			return right
+		}
+		return left
+	}
+}
+
+// MaxResolver returns a ConflictResolver that keeps the larger value.
+func MaxResolver[V Ordered]() ConflictResolver[V] {
+	return func(left, right V) V {
+		if right > left {
+			return right
+		}
+		return left
+	}
+}
+
+// ConcatResolver returns a ConflictResolver that concatenates the slices into a new
+// slice holding the elements of the existing slice followed by the new slice.
+func ConcatResolver[V ~[]E, E any]() ConflictResolver[V] {
+	return func(left, right V) V {
+		res := make(V, 0, len(left)+len(right))
+		res = append(res, left...)
+		return append(res, right...)
+	}
+}
+
+// FirstNonZeroResolver returns a ConflictResolver that keeps the existing value
+// unless it is the zero value, in which case the new value is used.
+func FirstNonZeroResolver[V comparable]() ConflictResolver[V] {
+	return func(left, right V) V {
+		var zero V
+		if left == zero {
+			return right
+		}
+		return left
+	}
+}
+
+// KeyedConflictResolver is a function type that is invoked when MergeWithKey is
+// merging maps that contain the same key. Unlike ConflictResolver it is given the
+// conflicting key and can fail to resolve the conflict by returning an error.
+type KeyedConflictResolver[K comparable, V any] func(key K, left, right V) (V, error)
+
+// KeyedResolver adapts a ConflictResolver to a KeyedConflictResolver that resolves
+// conflicts for every key the same way and never fails.
+func KeyedResolver[K comparable, V any](fn ConflictResolver[V]) KeyedConflictResolver[K, V] {
+	return func(key K, left, right V) (V, error) {
+		return fn(left, right), nil
+	}
+}
+
+// ErrMergeConflict is returned by StrictResolver for every conflicting key.
+var ErrMergeConflict = errors.New("conflicting values")
+
+// StrictResolver returns a KeyedConflictResolver that fails on every conflict with
+// ErrMergeConflict.
+func StrictResolver[K comparable, V any]() KeyedConflictResolver[K, V] {
+	return func(key K, left, right V) (V, error) {
+		return left, ErrMergeConflict
+	}
+}
+
+// MergeConflict describes a key MergeWithKey failed to resolve.
+type MergeConflict[K comparable, V any] struct {
+	Key   K
+	Left  V
+	Right V
+	// Err is the error returned by the KeyedConflictResolver.
+	Err error
+}
+
+// MergeConflictError is returned by MergeWithKey when conflicts could not be
+// resolved. It lists every conflict, sorted by key.
+type MergeConflictError[K comparable, V any] struct {
+	Conflicts []MergeConflict[K, V]
+}
+
+func (e *MergeConflictError[K, V]) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("%v (%v, %v): %v", c.Key, c.Left, c.Right, c.Err))
+	}
+	return fmt.Sprintf("merge conflicts on %d keys: %s", len(e.Conflicts), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of every conflict so errors.Is and errors.As can be used
+// to inspect them.
+func (e *MergeConflictError[K, V]) Unwrap() []error {
+	errs := make([]error, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		errs = append(errs, c.Err)
+	}
+	return errs
+}
+
 // Merge merges multiple maps into a single new map. If a key exists in multiple maps the
 // ConflictResolver function is called to resolve the conflict. The value returns by the
 // ConflictResolver is the value set in the new merged map.
@@ -43,6 +165,43 @@ func Merge[M ~map[K]V, K comparable, V any](fn ConflictResolver[V], src ...M) ma
 	return merged
 }
 
+// MergeWithKey merges multiple maps into a single new map like Merge, except the
+// KeyedConflictResolver is given the conflicting key and may fail. All conflicts
+// are attempted and if any of them fail a *MergeConflictError listing every failed
+// conflict is returned along with a nil map.
+func MergeWithKey[M ~map[K]V, K comparable, V any](fn KeyedConflictResolver[K, V], src ...M) (map[K]V, error) {
+	merged := make(map[K]V)
+	var conflicts []MergeConflict[K, V]
+	for _, m := range src {
+		for k, v := range m {
+			existing, ok := merged[k]
+			if !ok {
+				merged[k] = v
+				continue
+			}
+			newVal, err := fn(k, existing, v)
+			if err != nil {
+				conflicts = append(conflicts, MergeConflict[K, V]{
+					Key:   k,
+					Left:  existing,
+					Right: v,
+					Err:   err,
+				})
+				continue
+			}
+			merged[k] = newVal
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.SliceStable(conflicts, func(i, j int) bool {
+			return lessValues(conflicts[i].Key, conflicts[j].Key)
+		})
+		return nil, &MergeConflictError[K, V]{Conflicts: conflicts}
+	}
+	return merged, nil
+}
+
 // Keys returns all the keys in the provided map.
 //
 // The keys will be in an indeterminate order.
@@ -104,6 +263,97 @@ func SetIfAbsent[M ~map[K]V, K comparable, V any](m M, key K, val V) bool {
 	return false
 }
 
+// ComputeIfAbsent returns the value for the key if it exists. Otherwise the value is
+// computed by fn, set in the map and returned. fn is only called if the key doesn't
+// exist, so it's suited to values that are expensive to build:
+//
+//	conn, _ := ComputeIfAbsent(conns, addr, dial)
+//
+// The boolean result reports whether the map changed, meaning the value was
+// computed and set.
+func ComputeIfAbsent[M ~map[K]V, K comparable, V any](m M, key K, fn func(key K) V) (V, bool) {
+	if val, ok := m[key]; ok {
+		return val, false
+	}
+	val := fn(key)
+	m[key] = val
+	return val, true
+}
+
+// ComputeIfPresent computes a new value for the key from its current value if the
+// key exists. If fn returns true the new value is set in the map, otherwise the key
+// is removed and the zero value returned.
+//
+// The boolean result reports whether the map changed like Compute. It is false if
+// the key doesn't exist, in which case fn isn't called, or if fn returned true with
+// a value equal to the current value.
+func ComputeIfPresent[M ~map[K]V, K comparable, V any](m M, key K, fn func(key K, val V) (V, bool)) (V, bool) {
+	val, ok := m[key]
+	if !ok {
+		return val, false
+	}
+	return computeValue(m, key, val, ok, func(key K, val V, _ bool) (V, bool) {
+		return fn(key, val)
+	})
+}
+
+// Compute computes a new value for the key from its current value, which is the
+// zero value if the key doesn't exist as reported by exists. If fn returns true the
+// new value is set in the map, otherwise the key is removed if it exists and the
+// zero value returned. For example grouping values by a key:
+//
+//	Compute(groups, key, func(_ string, group []string, _ bool) ([]string, bool) {
+//		return append(group, val), true
+//	})
+//
+// The boolean result reports whether the map changed, meaning the key was added or
+// removed, or its value was replaced by a value that isn't equal to it according
+// to reflect.DeepEqual.
+func Compute[M ~map[K]V, K comparable, V any](m M, key K, fn func(key K, val V, exists bool) (V, bool)) (V, bool) {
+	val, ok := m[key]
+	return computeValue(m, key, val, ok, fn)
+}
+
+func computeValue[M ~map[K]V, K comparable, V any](m M, key K, val V, exists bool, fn func(key K, val V, exists bool) (V, bool)) (V, bool) {
+	newVal, keep := fn(key, val, exists)
+	if keep {
+		m[key] = newVal
+		return newVal, !exists || valueChanged(val, newVal)
+	}
+	var zero V
+	if !exists {
+		return zero, false
+	}
+	delete(m, key)
+	return zero, true
+}
+
+// MergeValue sets the value for the key if it doesn't exist. Otherwise the existing
+// value and val are resolved by the ConflictResolver like Merge and the result is
+// set. For example counting occurrences:
+//
+//	MergeValue(counts, word, 1, SumResolver[int]())
+//
+// The resolved value is returned along with whether the map changed like Compute,
+// which is false if the ConflictResolver kept the existing value.
+func MergeValue[M ~map[K]V, K comparable, V any](m M, key K, val V, fn ConflictResolver[V]) (V, bool) {
+	existing, ok := m[key]
+	if !ok {
+		m[key] = val
+		return val, true
+	}
+	val = fn(existing, val)
+	m[key] = val
+	return val, valueChanged(existing, val)
+}
+
+// valueChanged reports whether a value replaced by Compute or MergeValue differs
+// from the previous value. The values are compared with reflect.DeepEqual since
+// they may not be comparable.
+func valueChanged[V any](old, new V) bool {
+	return !reflect.DeepEqual(old, new)
+}
+
 // Clear removes all entries from the map.
 func Clear[M ~map[K]V, K comparable, V any](m M) {
 	for key := range m {
@@ -135,7 +385,12 @@ func Copy[M ~map[K]V, K comparable, V any](src, dst M) {
 }
 
 // Equal compares two maps and returns a boolean value indicating if they are equal.
-func Equal[M ~map[K]V, K, V comparable](m1, m2 M) bool {
+// Options can be provided to control which keys are compared and how their values
+// are compared.
+func Equal[M ~map[K]V, K, V comparable](m1, m2 M, opts ...DiffOption[K, V]) bool {
+	if len(opts) > 0 {
+		return equalWithPolicy(m1, m2, newDiffPolicy[K, V](opts))
+	}
 	if len(m1) != len(m2) {
 		return false
 	}
@@ -147,8 +402,29 @@ func Equal[M ~map[K]V, K, V comparable](m1, m2 M) bool {
 	return true
 }
 
+func equalWithPolicy[M ~map[K]V, K, V comparable](m1, m2 M, policy *diffPolicy[K, V]) bool {
+	eq := func(a, b V) bool { return a == b }
+	for k, v1 := range m1 {
+		if !policy.includes(k) {
+			continue
+		}
+		if v2, ok := m2[k]; !ok || !policy.equal(k, v1, v2, eq) {
+			return false
+		}
+	}
+	for k := range m2 {
+		if !policy.includes(k) {
+			continue
+		}
+		if _, ok := m1[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // Entry is a data structure representing a single entry in a map.
-type Entry[K comparable, V any] struct {
+type Entry[K any, V any] struct {
 	Key   K
 	Value V
 }
@@ -191,7 +467,7 @@ func MapToSlice[M ~map[K]V, K comparable, V any, R any](m M, mapper func(key K,
 }
 
 // Predicate represents a predicate (boolean-value function).
-type Predicate[K comparable, V any] func(key K, val V) bool
+type Predicate[K any, V any] func(key K, val V) bool
 
 // Filter iterates through the entries of the map and tests if they satisfy the predicate.
 // Entries that satisfy the predicate added to a newly returned map, effectively filtering
@@ -218,6 +494,72 @@ func TakeIf[M ~map[K]V, K comparable, V any](m M, pred Predicate[K, V], fn func(
 	}
 }
 
+// DeleteIf removes the entries of the map that satisfy the predicate and returns the
+// number of entries removed. Unlike Filter the map is modified in place rather than
+// copied.
+func DeleteIf[M ~map[K]V, K comparable, V any](m M, pred Predicate[K, V]) int {
+	removed := 0
+	// Deleting the entry currently visited by range is safe, it doesn't affect which
+	// of the remaining entries are visited.
+	for k, v := range m {
+		if pred(k, v) && deleteKey(m, k) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// deleteKey deletes the key and reports whether an entry was removed, which isn't
+// the case for keys that aren't equal to themselves such as NaN.
+func deleteKey[M ~map[K]V, K comparable, V any](m M, key K) bool {
+	n := len(m)
+	delete(m, key)
+	return len(m) < n
+}
+
+// Retain removes the entries of the map that don't satisfy the predicate, keeping
+// only the entries Filter would return, and returns the number of entries removed.
+func Retain[M ~map[K]V, K comparable, V any](m M, pred Predicate[K, V]) int {
+	return DeleteIf(m, func(key K, val V) bool {
+		return !pred(key, val)
+	})
+}
+
+// UpdateValues replaces every value of the map with the value returned by fn.
+func UpdateValues[M ~map[K]V, K comparable, V any](m M, fn func(key K, val V) V) {
+	for k, v := range m {
+		m[k] = fn(k, v)
+	}
+}
+
+// Drain removes every entry from the map, passing each entry to fn after it was
+// removed, and returns the number of entries drained. Entries set by fn while
+// draining are drained as well, so the map is empty once Drain returns. Entries fn
+// removes itself aren't passed to fn.
+//
+// Entries whose key can't be deleted because it isn't equal to itself, such as NaN,
+// are left in the map and aren't passed to fn.
+func Drain[M ~map[K]V, K comparable, V any](m M, fn func(key K, val V)) int {
+	drained := 0
+	// Entries added while ranging may or may not be visited, so ranging is repeated
+	// until a pass removes nothing.
+	for {
+		removed := 0
+		for k, v := range m {
+			if !deleteKey(m, k) {
+				continue
+			}
+			removed++
+			fn(k, v)
+		}
+		if removed == 0 {
+			return drained
+		}
+		drained += removed
+	}
+}
+
+// DiffReason describes why an entry differs between two maps.
 type DiffReason int
 
 const (
@@ -229,53 +571,102 @@ const (
 	// DiffMissingRight is a flag indicating a key exist in the left map but doesn't
 	// exist in the right map
 	DiffMissingRight DiffReason = 2
+	// DiffTypeChanged is a flag indicating the values for a given key are of
+	// different types
+	DiffTypeChanged DiffReason = 3
+)
+
+const (
+	// DiffModified is an alias of DiffValue
+	DiffModified = DiffValue
+	// DiffAdded is an alias of DiffMissingLeft, the entry was added to the right map
+	DiffAdded = DiffMissingLeft
+	// DiffRemoved is an alias of DiffMissingRight, the entry was removed from the
+	// right map
+	DiffRemoved = DiffMissingRight
 )
 
+var diffReasonNames = map[DiffReason]string{
+	DiffModified:    "modified",
+	DiffAdded:       "added",
+	DiffRemoved:     "removed",
+	DiffTypeChanged: "type-changed",
+}
+
+// String returns the name of the DiffReason: "modified", "added", "removed" or
+// "type-changed".
+func (r DiffReason) String() string {
+	if name, ok := diffReasonNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("DiffReason(%d)", int(r))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r DiffReason) MarshalText() ([]byte, error) {
+	if _, ok := diffReasonNames[r]; !ok {
+		return nil, fmt.Errorf("invalid DiffReason %d", int(r))
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *DiffReason) UnmarshalText(text []byte) error {
+	for reason, name := range diffReasonNames {
+		if name == string(text) {
+			*r = reason
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid DiffReason %q", text)
+}
+
+// EntryComparison describes how a single key differs between two maps.
 type EntryComparison[V comparable] struct {
 	Left   V
 	Right  V
-	Diff   string
 	Reason DiffReason
 }
 
+// Diff returns a human-readable diff of the left and right values for this entry.
+// The diff is computed on demand from the entry's values only, so it is cheap
+// compared to rendering the entire maps.
+func (e EntryComparison[V]) Diff() string {
+	return cmp.Diff(e.Left, e.Right)
+}
+
 // Diff compares two maps and returns a map containing the keys that differ along
 // with the differences.
 func Diff[M ~map[K]V, K, V comparable](left M, right M) map[K]EntryComparison[V] {
 	res := make(map[K]EntryComparison[V])
 	for key, val := range left {
 		otherVal, ok := right[key]
-		if !ok || val != otherVal {
-			var reason DiffReason
-			if !ok {
-				reason = DiffMissingRight
-			}
-			if ok && val != otherVal {
-				reason = DiffValue
+		if !ok {
+			res[key] = EntryComparison[V]{
+				Left:   val,
+				Right:  otherVal,
+				Reason: DiffMissingRight,
 			}
+			continue
+		}
+		if val != otherVal {
 			res[key] = EntryComparison[V]{
 				Left:   val,
 				Right:  otherVal,
-				Diff:   cmp.Diff(left, right),
-				Reason: reason,
+				Reason: DiffValue,
 			}
 		}
 	}
 
+	// Keys present in both maps were handled above, only keys missing from the
+	// left map remain.
 	for key, val := range right {
-		otherVal, ok := left[key]
-		if !ok || val != otherVal {
-			var reason DiffReason
-			if !ok {
-				reason = DiffMissingLeft
-			}
-			if ok && val != otherVal {
-				reason = DiffValue
-			}
+		if _, ok := left[key]; !ok {
+			var zero V
 			res[key] = EntryComparison[V]{
-				Left:   otherVal,
+				Left:   zero,
 				Right:  val,
-				Diff:   cmp.Diff(left, right),
-				Reason: reason,
+				Reason: DiffMissingLeft,
 			}
 		}
 	}
@@ -283,21 +674,51 @@ func Diff[M ~map[K]V, K, V comparable](left M, right M) map[K]EntryComparison[V]
 	return res
 }
 
+// Report is the result of comparing two maps in their entirety. Entries holds the
+// per-key differences while String renders the whole-map diff. The whole-map diff
+// is only computed when String is called.
+type Report[K, V comparable] struct {
+	Left    map[K]V
+	Right   map[K]V
+	Entries map[K]EntryComparison[V]
+}
+
+// NewReport compares two maps and returns a Report describing the differences.
+func NewReport[M ~map[K]V, K, V comparable](left M, right M) Report[K, V] {
+	return Report[K, V]{
+		Left:    left,
+		Right:   right,
+		Entries: Diff(left, right),
+	}
+}
+
+// Equal returns true if the maps in the Report have no differences.
+func (r Report[K, V]) Equal() bool {
+	return len(r.Entries) == 0
+}
+
+// String renders a human-readable diff of the entire left and right maps.
+func (r Report[K, V]) String() string {
+	return cmp.Diff(r.Left, r.Right)
+}
+
 // KeyDiff inspects the left and right map returning two slices of keys: the keys
 // in the left map that don't exist in the right map, and the keys that exist
-// in the right map but don't exist in the left map.
-func KeyDiff[M ~map[K]V, K comparable, V any](left M, right M) ([]K, []K) {
+// in the right map but don't exist in the left map. Options can be provided to
+// control which keys are compared.
+func KeyDiff[M ~map[K]V, K comparable, V any](left M, right M, opts ...DiffOption[K, V]) ([]K, []K) {
 	leftKeys := make([]K, 0)
 	rightKeys := make([]K, 0)
+	policy := newDiffPolicy[K, V](opts)
 
 	for k := range left {
-		if _, ok := right[k]; !ok {
+		if _, ok := right[k]; !ok && policy.includes(k) {
 			leftKeys = append(leftKeys, k)
 		}
 	}
 
 	for k := range right {
-		if _, ok := left[k]; !ok {
+		if _, ok := left[k]; !ok && policy.includes(k) {
 			rightKeys = append(rightKeys, k)
 		}
 	}
@@ -314,3 +735,116 @@ func Invert[M ~map[K]V, K, V comparable](m M) map[V]K {
 	}
 	return res
 }
+
+// InvertMulti creates a map composed of inverted keys and values where every key
+// sharing the same value is kept.
+//
+// The keys for each value will be in an indeterminate order, use InvertMultiSorted
+// if a deterministic order is required.
+func InvertMulti[M ~map[K]V, K, V comparable](m M) map[V][]K {
+	res := make(map[V][]K, len(m))
+	for k, v := range m {
+		res[v] = append(res[v], k)
+	}
+	return res
+}
+
+// InvertMultiSorted is like InvertMulti except the keys for each value are sorted in
+// ascending order.
+func InvertMultiSorted[M ~map[K]V, K Ordered, V comparable](m M) map[V][]K {
+	res := InvertMulti(m)
+	for _, keys := range res {
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i] < keys[j]
+		})
+	}
+	return res
+}
+
+// InvertStrict creates a map composed of inverted keys and values. If any value is
+// shared by multiple keys a *DuplicateValueError listing every duplicated value is
+// returned along with a nil map.
+func InvertStrict[M ~map[K]V, K, V comparable](m M) (map[V]K, error) {
+	return InvertFunc(m, func(val V) V {
+		return val
+	})
+}
+
+// InvertFunc creates a map keyed by the projection of each value, mapped to the key
+// of the value. For example, a map of users keyed by ID can be indexed by email.
+// If any projection is shared by multiple keys a *DuplicateValueError listing every
+// duplicated projection is returned along with a nil map.
+func InvertFunc[M ~map[K]V, K comparable, V any, P comparable](m M, fn func(val V) P) (map[P]K, error) {
+	res := make(map[P]K, len(m))
+	var duplicates map[P][]K
+	for k, v := range m {
+		p := fn(v)
+		existing, ok := res[p]
+		if !ok {
+			res[p] = k
+			continue
+		}
+		if duplicates == nil {
+			duplicates = make(map[P][]K)
+		}
+		if len(duplicates[p]) == 0 {
+			duplicates[p] = append(duplicates[p], existing)
+		}
+		duplicates[p] = append(duplicates[p], k)
+	}
+
+	if len(duplicates) > 0 {
+		for _, keys := range duplicates {
+			sortKeys(keys)
+		}
+		return nil, &DuplicateValueError[K, P]{Duplicates: duplicates}
+	}
+	return res, nil
+}
+
+// DuplicateValueError is returned when a map cannot be inverted without losing
+// keys because multiple keys share the same value. Duplicates maps every shared
+// value to the keys sharing it, sorted.
+type DuplicateValueError[K, V comparable] struct {
+	Duplicates map[V][]K
+}
+
+func (e *DuplicateValueError[K, V]) Error() string {
+	values := Keys(e.Duplicates)
+	sortKeys(values)
+
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		keys := make([]string, 0, len(e.Duplicates[v]))
+		for _, k := range e.Duplicates[v] {
+			keys = append(keys, fmt.Sprint(k))
+		}
+		parts = append(parts, fmt.Sprintf("%v (%s)", v, strings.Join(keys, ", ")))
+	}
+	return fmt.Sprintf("values shared by multiple keys: %s", strings.Join(parts, "; "))
+}
+
+// sortKeys sorts keys in their natural order when their underlying type is a
+// string or number, otherwise by their formatted representation.
+func sortKeys[K comparable](keys []K) {
+	sort.Slice(keys, func(i, j int) bool {
+		return lessValues(keys[i], keys[j])
+	})
+}
+
+func lessValues(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.String:
+			return va.String() < vb.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return va.Int() < vb.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return va.Uint() < vb.Uint()
+		case reflect.Float32, reflect.Float64:
+			return va.Float() < vb.Float()
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
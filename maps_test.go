@@ -1,6 +1,8 @@
 package maps
 
 import (
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"testing"
@@ -73,6 +75,88 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestMergeWithKey(t *testing.T) {
+	counters := map[string]int{"requests": 10, "errors": 1, "labels.env": 1}
+	other := map[string]int{"requests": 5, "labels.env": 2, "latency": 7}
+
+	resolver := func(key string, left, right int) (int, error) {
+		if strings.HasPrefix(key, "labels.") {
+			return right, nil
+		}
+		return left + right, nil
+	}
+
+	actual, err := MergeWithKey(resolver, counters, other)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"requests": 15, "errors": 1, "labels.env": 2, "latency": 7}, actual)
+
+	actual, err = MergeWithKey(KeyedResolver[string](OverwriteResolver[int]()), counters, other)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"requests": 5, "errors": 1, "labels.env": 2, "latency": 7}, actual)
+}
+
+func TestMergeWithKey_Conflicts(t *testing.T) {
+	secretsResolver := func(key string, left, right string) (string, error) {
+		if strings.HasPrefix(key, "secrets.") {
+			return "", fmt.Errorf("refusing to override %s", key)
+		}
+		return right, nil
+	}
+
+	actual, err := MergeWithKey(secretsResolver,
+		map[string]string{"secrets.db": "a", "secrets.api": "b", "name": "x"},
+		map[string]string{"secrets.db": "c", "secrets.api": "d", "name": "y"},
+	)
+	assert.Nil(t, actual)
+
+	var conflictErr *MergeConflictError[string, string]
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, []MergeConflict[string, string]{
+		{Key: "secrets.api", Left: "b", Right: "d", Err: fmt.Errorf("refusing to override secrets.api")},
+		{Key: "secrets.db", Left: "a", Right: "c", Err: fmt.Errorf("refusing to override secrets.db")},
+	}, conflictErr.Conflicts)
+	assert.Equal(t, "merge conflicts on 2 keys: "+
+		"secrets.api (b, d): refusing to override secrets.api; "+
+		"secrets.db (a, c): refusing to override secrets.db", err.Error())
+}
+
+func TestStrictResolver(t *testing.T) {
+	actual, err := MergeWithKey(StrictResolver[string, int](),
+		map[string]int{"red": 1, "blue": 2},
+		map[string]int{"green": 3},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"red": 1, "blue": 2, "green": 3}, actual)
+
+	actual, err = MergeWithKey(StrictResolver[string, int](),
+		map[string]int{"red": 1, "blue": 2},
+		map[string]int{"red": 1, "blue": 3},
+	)
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, ErrMergeConflict)
+	assert.Len(t, err.(*MergeConflictError[string, int]).Conflicts, 2)
+}
+
+func TestResolvers(t *testing.T) {
+	left := map[string]int{"a": 1, "b": 5, "c": 0}
+	right := map[string]int{"a": 3, "b": 2, "c": 4}
+
+	assert.Equal(t, map[string]int{"a": 4, "b": 7, "c": 4}, Merge(SumResolver[int](), left, right))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 0}, Merge(MinResolver[int](), left, right))
+	assert.Equal(t, map[string]int{"a": 3, "b": 5, "c": 4}, Merge(MaxResolver[int](), left, right))
+	assert.Equal(t, map[string]int{"a": 1, "b": 5, "c": 4}, Merge(FirstNonZeroResolver[int](), left, right))
+	assert.Equal(t, map[string]string{"k": "apple"},
+		Merge(MinResolver[string](), map[string]string{"k": "banana"}, map[string]string{"k": "apple"}))
+
+	first := []string{"a"}
+	concat := Merge(ConcatResolver[[]string](),
+		map[string][]string{"tags": first},
+		map[string][]string{"tags": {"b", "c"}},
+	)
+	assert.Equal(t, map[string][]string{"tags": {"a", "b", "c"}}, concat)
+	assert.Equal(t, []string{"a"}, first)
+}
+
 func TestKeys(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -352,6 +436,201 @@ func TestSetIfAbsent(t *testing.T) {
 	}
 }
 
+func TestComputeIfAbsent(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       map[string]int
+		key      string
+		expected map[string]int
+		val      int
+		changed  bool
+		calls    int
+	}{
+		{
+			name:     "Value is not Present",
+			in:       map[string]int{"red": 1},
+			key:      "white",
+			expected: map[string]int{"red": 1, "white": 5},
+			val:      5,
+			changed:  true,
+			calls:    1,
+		},
+		{
+			name:     "Value is Present",
+			in:       map[string]int{"red": 1},
+			key:      "red",
+			expected: map[string]int{"red": 1},
+			val:      1,
+			changed:  false,
+			calls:    0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			val, changed := ComputeIfAbsent(test.in, test.key, func(key string) int {
+				calls++
+				return len(key)
+			})
+			assert.Equal(t, test.val, val)
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.calls, calls)
+			assert.Equal(t, test.expected, test.in)
+		})
+	}
+}
+
+func TestComputeIfPresent(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       map[string]int
+		key      string
+		fn       func(key string, val int) (int, bool)
+		expected map[string]int
+		val      int
+		changed  bool
+	}{
+		{
+			name:     "Value is Updated",
+			in:       map[string]int{"red": 1, "blue": 2},
+			key:      "red",
+			fn:       func(key string, val int) (int, bool) { return val + 10, true },
+			expected: map[string]int{"red": 11, "blue": 2},
+			val:      11,
+			changed:  true,
+		},
+		{
+			name:     "Value is Unchanged",
+			in:       map[string]int{"red": 1, "blue": 2},
+			key:      "red",
+			fn:       func(key string, val int) (int, bool) { return val, true },
+			expected: map[string]int{"red": 1, "blue": 2},
+			val:      1,
+			changed:  false,
+		},
+		{
+			name:     "Value is Removed",
+			in:       map[string]int{"red": 1, "blue": 2},
+			key:      "blue",
+			fn:       func(key string, val int) (int, bool) { return 0, false },
+			expected: map[string]int{"red": 1},
+			val:      0,
+			changed:  true,
+		},
+		{
+			name: "Value is not Present",
+			in:   map[string]int{"red": 1},
+			key:  "white",
+			fn: func(key string, val int) (int, bool) {
+				t.Error("fn must not be called for absent keys")
+				return 0, true
+			},
+			expected: map[string]int{"red": 1},
+			val:      0,
+			changed:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			val, changed := ComputeIfPresent(test.in, test.key, test.fn)
+			assert.Equal(t, test.val, val)
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.expected, test.in)
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	groups := make(map[int][]string)
+	for _, word := range []string{"go", "map", "ok", "key"} {
+		Compute(groups, len(word), func(_ int, group []string, _ bool) ([]string, bool) {
+			return append(group, word), true
+		})
+	}
+	assert.Equal(t, map[int][]string{2: {"go", "ok"}, 3: {"map", "key"}}, groups)
+
+	tests := []struct {
+		name     string
+		in       map[string]int
+		key      string
+		keep     bool
+		expected map[string]int
+		val      int
+		changed  bool
+		exists   bool
+	}{
+		{
+			name:     "Present and Kept",
+			in:       map[string]int{"red": 1},
+			key:      "red",
+			keep:     true,
+			expected: map[string]int{"red": 2},
+			val:      2,
+			changed:  true,
+			exists:   true,
+		},
+		{
+			name:     "Present and Removed",
+			in:       map[string]int{"red": 1},
+			key:      "red",
+			keep:     false,
+			expected: map[string]int{},
+			val:      0,
+			changed:  true,
+			exists:   true,
+		},
+		{
+			name:     "Absent and Set",
+			in:       map[string]int{"red": 1},
+			key:      "blue",
+			keep:     true,
+			expected: map[string]int{"red": 1, "blue": 1},
+			val:      1,
+			changed:  true,
+		},
+		{
+			name:     "Absent and Not Set",
+			in:       map[string]int{"red": 1},
+			key:      "blue",
+			keep:     false,
+			expected: map[string]int{"red": 1},
+			val:      0,
+			changed:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			val, changed := Compute(test.in, test.key, func(key string, val int, exists bool) (int, bool) {
+				assert.Equal(t, test.exists, exists)
+				return val + 1, test.keep
+			})
+			assert.Equal(t, test.val, val)
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.expected, test.in)
+		})
+	}
+}
+
+func TestMergeValue(t *testing.T) {
+	counts := make(map[string]int)
+	for _, word := range []string{"a", "b", "a", "c", "a"} {
+		_, changed := MergeValue(counts, word, 1, SumResolver[int]())
+		assert.True(t, changed)
+	}
+	assert.Equal(t, map[string]int{"a": 3, "b": 1, "c": 1}, counts)
+
+	val, changed := MergeValue(counts, "a", 10, MaxResolver[int]())
+	assert.Equal(t, 10, val)
+	assert.True(t, changed)
+	val, changed = MergeValue(counts, "b", 10, NopResolver[int]())
+	assert.Equal(t, 1, val)
+	assert.False(t, changed)
+	assert.Equal(t, map[string]int{"a": 10, "b": 1, "c": 1}, counts)
+}
+
 func TestClear(t *testing.T) {
 	in := map[string]int{
 		"red":    1,
@@ -693,6 +972,111 @@ func TestTakeIf(t *testing.T) {
 	}
 }
 
+func TestDeleteIf(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       map[string]int
+		pred     Predicate[string, int]
+		expected map[string]int
+		removed  int
+	}{
+		{
+			name:     "Some Entries Removed",
+			in:       map[string]int{"red": 1, "blue": 2, "yellow": 3, "green": 4},
+			pred:     func(key string, val int) bool { return val%2 == 0 },
+			expected: map[string]int{"red": 1, "yellow": 3},
+			removed:  2,
+		},
+		{
+			name:     "All Entries Removed",
+			in:       map[string]int{"red": 1, "blue": 2},
+			pred:     func(key string, val int) bool { return true },
+			expected: map[string]int{},
+			removed:  2,
+		},
+		{
+			name:     "No Entries Removed",
+			in:       map[string]int{"red": 1, "blue": 2},
+			pred:     func(key string, val int) bool { return false },
+			expected: map[string]int{"red": 1, "blue": 2},
+			removed:  0,
+		},
+		{
+			name:     "Nil Map",
+			in:       nil,
+			pred:     func(key string, val int) bool { return true },
+			expected: nil,
+			removed:  0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			removed := DeleteIf(test.in, test.pred)
+			assert.Equal(t, test.removed, removed)
+			assert.Equal(t, test.expected, test.in)
+		})
+	}
+}
+
+func TestDeleteIf_NaNKeys(t *testing.T) {
+	m := map[float64]int{math.NaN(): 1, math.NaN(): 2, 3: 3}
+	removed := DeleteIf(m, func(key float64, val int) bool { return true })
+	assert.Equal(t, 1, removed)
+	assert.Len(t, m, 2)
+}
+
+func TestRetain(t *testing.T) {
+	m := map[string]int{"red": 1, "blue": 2, "yellow": 3, "green": 4}
+	expected := Filter(m, func(key string, val int) bool { return len(key) > 3 })
+
+	removed := Retain(m, func(key string, val int) bool { return len(key) > 3 })
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, expected, m)
+}
+
+func TestUpdateValues(t *testing.T) {
+	m := map[string]int{"red": 1, "blue": 2, "yellow": 3}
+	UpdateValues(m, func(key string, val int) int {
+		return val * len(key)
+	})
+	assert.Equal(t, map[string]int{"red": 3, "blue": 8, "yellow": 18}, m)
+}
+
+func TestDrain(t *testing.T) {
+	m := map[string]int{"red": 1, "blue": 2, "yellow": 3}
+	drained := make(map[string]int)
+	n := Drain(m, func(key string, val int) {
+		drained[key] = val
+	})
+	assert.Equal(t, 3, n)
+	assert.Empty(t, m)
+	assert.Equal(t, map[string]int{"red": 1, "blue": 2, "yellow": 3}, drained)
+
+	// Entries set while draining, such as retries scheduled by fn, are drained too.
+	queue := map[string]int{"a": 2, "b": 0}
+	var visited []string
+	n = Drain(queue, func(key string, attempts int) {
+		visited = append(visited, key)
+		if attempts > 0 {
+			queue[key+"'"] = attempts - 1
+		}
+	})
+	assert.Equal(t, 4, n)
+	assert.Empty(t, queue)
+	assert.ElementsMatch(t, []string{"a", "a'", "a''", "b"}, visited)
+
+	// NaN keys can't be deleted, so they are left in the map instead of looping.
+	nan := map[float64]int{math.NaN(): 1, 2: 2}
+	var keys []float64
+	n = Drain(nan, func(key float64, val int) {
+		keys = append(keys, key)
+	})
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []float64{2}, keys)
+	assert.Len(t, nan, 1)
+}
+
 func TestDiff(t *testing.T) {
 	m1 := map[string]int{
 		"red":   1,
@@ -728,6 +1112,66 @@ func TestDiff(t *testing.T) {
 	assert.Equal(t, 0, val.Left)
 	assert.Equal(t, 4, val.Right)
 	assert.Equal(t, DiffMissingLeft, val.Reason)
+	assert.Contains(t, val.Diff(), "4")
+}
+
+func TestEntryComparison_Diff(t *testing.T) {
+	ec := EntryComparison[string]{
+		Left:   "red",
+		Right:  "blue",
+		Reason: DiffValue,
+	}
+	diff := ec.Diff()
+	assert.Contains(t, diff, `"red"`)
+	assert.Contains(t, diff, `"blue"`)
+
+	ec = EntryComparison[string]{Left: "red", Right: "red"}
+	assert.Equal(t, "", ec.Diff())
+}
+
+func TestNewReport(t *testing.T) {
+	m1 := map[string]int{
+		"red":  1,
+		"blue": 2,
+	}
+	m2 := map[string]int{
+		"red":  1,
+		"blue": 3,
+	}
+
+	report := NewReport(m1, m2)
+	assert.False(t, report.Equal())
+	assert.Equal(t, 1, len(report.Entries))
+	assert.Equal(t, DiffValue, report.Entries["blue"].Reason)
+	assert.Contains(t, report.String(), `"blue"`)
+
+	report = NewReport(m1, m1)
+	assert.True(t, report.Equal())
+	assert.Equal(t, "", report.String())
+}
+
+func BenchmarkDiff(b *testing.B) {
+	for _, size := range []int{1_000, 10_000, 100_000} {
+		left := make(map[int]int, size)
+		right := make(map[int]int, size)
+		for i := 0; i < size; i++ {
+			left[i] = i
+			// Half of the entries differ between the two maps.
+			if i%2 == 0 {
+				right[i] = i
+			} else {
+				right[i] = -i
+			}
+		}
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Diff(left, right)
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*size), "ns/entry")
+		})
+	}
 }
 
 func TestMapToSlice(t *testing.T) {
@@ -829,3 +1273,73 @@ func TestInvert(t *testing.T) {
 		})
 	}
 }
+
+func TestInvertMulti(t *testing.T) {
+	in := map[string]int{
+		"red":    1,
+		"blue":   2,
+		"green":  1,
+		"orange": 3,
+		"pink":   1,
+	}
+
+	actual := InvertMulti(in)
+	assert.Equal(t, 3, len(actual))
+	assert.ElementsMatch(t, []string{"red", "green", "pink"}, actual[1])
+	assert.Equal(t, []string{"blue"}, actual[2])
+	assert.Equal(t, []string{"orange"}, actual[3])
+
+	assert.Equal(t, map[int][]string{
+		1: {"green", "pink", "red"},
+		2: {"blue"},
+		3: {"orange"},
+	}, InvertMultiSorted(in))
+}
+
+func TestInvertStrict(t *testing.T) {
+	actual, err := InvertStrict(map[string]int{"red": 1, "blue": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "red", 2: "blue"}, actual)
+
+	actual, err = InvertStrict(map[string]int{
+		"red":    1,
+		"green":  1,
+		"pink":   1,
+		"blue":   2,
+		"navy":   2,
+		"orange": 3,
+	})
+	assert.Nil(t, actual)
+
+	var dupErr *DuplicateValueError[string, int]
+	assert.ErrorAs(t, err, &dupErr)
+	assert.Equal(t, map[int][]string{
+		1: {"green", "pink", "red"},
+		2: {"blue", "navy"},
+	}, dupErr.Duplicates)
+	assert.Equal(t, "values shared by multiple keys: 1 (green, pink, red); 2 (blue, navy)", err.Error())
+}
+
+func TestInvertFunc(t *testing.T) {
+	type user struct {
+		Name  string
+		Email string
+	}
+
+	users := map[int]user{
+		1: {Name: "Alice", Email: "alice@example.com"},
+		2: {Name: "Bob", Email: "bob@example.com"},
+	}
+	byEmail, err := InvertFunc(users, func(u user) string {
+		return u.Email
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"alice@example.com": 1, "bob@example.com": 2}, byEmail)
+
+	users[3] = user{Name: "Robert", Email: "bob@example.com"}
+	byEmail, err = InvertFunc(users, func(u user) string {
+		return u.Email
+	})
+	assert.Nil(t, byEmail)
+	assert.EqualError(t, err, "values shared by multiple keys: bob@example.com (2, 3)")
+}
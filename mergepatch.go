@@ -0,0 +1,88 @@
+package maps
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to a copy of target and
+// returns the patched copy. Keys in the patch with a nil value are removed from
+// the result, nested maps are merged recursively, and every other value, including
+// slices, replaces the value in target wholesale.
+//
+// Neither target nor patch is modified and the result doesn't share memory with
+// either of them.
+func ApplyMergePatch(target, patch map[string]any) map[string]any {
+	return mergePatchValue(target, patch).(map[string]any)
+}
+
+// mergePatchValue implements the MergePatch function from section 2 of RFC 7386
+// for arbitrary document values.
+func mergePatchValue(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return deepCopyValue(patch)
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok || targetObj == nil {
+		targetObj = make(map[string]any, len(patchObj))
+	} else {
+		targetObj = deepCopyDocument(targetObj)
+	}
+	mergePatchInto(targetObj, patchObj)
+	return targetObj
+}
+
+// mergePatchInto applies patch to target in place. target must not share memory
+// with any caller provided document.
+func mergePatchInto(target, patch map[string]any) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		patchObj, ok := v.(map[string]any)
+		if !ok {
+			target[k] = deepCopyValue(v)
+			continue
+		}
+		existing, ok := target[k].(map[string]any)
+		if !ok || existing == nil {
+			existing = make(map[string]any, len(patchObj))
+			target[k] = existing
+		}
+		mergePatchInto(existing, patchObj)
+	}
+}
+
+// CreateMergePatch returns the JSON Merge Patch (RFC 7386) that transforms the
+// original document into the modified document. Keys removed from the original
+// are set to nil, nested maps are diffed recursively and any other value that
+// changed, including slices, is included in full.
+//
+// Because a nil value in a merge patch removes the key, a merge patch cannot set a
+// key to nil. Keys whose value is changed to nil in the modified document are
+// therefore removed when the patch is applied.
+func CreateMergePatch(original, modified map[string]any) map[string]any {
+	patch := make(map[string]any)
+	for k := range original {
+		if _, ok := modified[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, mv := range modified {
+		ov, ok := original[k]
+		if !ok {
+			patch[k] = deepCopyValue(mv)
+			continue
+		}
+		originalObj, ok1 := ov.(map[string]any)
+		modifiedObj, ok2 := mv.(map[string]any)
+		if ok1 && ok2 {
+			if nested := CreateMergePatch(originalObj, modifiedObj); len(nested) > 0 {
+				patch[k] = nested
+			}
+			continue
+		}
+		if !jsonEqual(ov, mv) {
+			patch[k] = deepCopyValue(mv)
+		}
+	}
+	return patch
+}
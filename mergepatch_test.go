@@ -0,0 +1,110 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc7386Examples are the test cases from Appendix A of RFC 7386.
+var rfc7386Examples = []struct {
+	original string
+	patch    string
+	result   string
+}{
+	{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+	{`{"a":"b"}`, `{"a":null}`, `{}`},
+	{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+	{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+	{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+	{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+	{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+	{`{"a":"b"}`, `["c"]`, `["c"]`},
+	{`{"a":"foo"}`, `null`, `null`},
+	{`{"a":"foo"}`, `"bar"`, `"bar"`},
+	{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+	{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+	{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+}
+
+func TestMergePatchValue_RFC7386(t *testing.T) {
+	for _, example := range rfc7386Examples {
+		t.Run(example.original+" "+example.patch, func(t *testing.T) {
+			original := decodeJSON[any](t, example.original)
+			patch := decodeJSON[any](t, example.patch)
+
+			actual := mergePatchValue(original, patch)
+			assert.Equal(t, decodeJSON[any](t, example.result), actual)
+			assert.Equal(t, decodeJSON[any](t, example.original), original)
+		})
+	}
+}
+
+func TestApplyMergePatch_RFC7386(t *testing.T) {
+	for _, example := range rfc7386Examples {
+		original, ok1 := decodeJSON[any](t, example.original).(map[string]any)
+		patch, ok2 := decodeJSON[any](t, example.patch).(map[string]any)
+		if !ok1 || !ok2 {
+			// ApplyMergePatch only operates on objects.
+			continue
+		}
+		t.Run(example.original+" "+example.patch, func(t *testing.T) {
+			actual := ApplyMergePatch(original, patch)
+			assert.Equal(t, decodeJSON[map[string]any](t, example.result), actual)
+		})
+	}
+}
+
+func TestApplyMergePatch_DoesNotShareMemory(t *testing.T) {
+	target := map[string]any{"a": map[string]any{"b": 1}, "untouched": map[string]any{"c": 1}}
+	patch := map[string]any{"a": map[string]any{"d": []any{1}}}
+
+	actual := ApplyMergePatch(target, patch)
+	actual["a"].(map[string]any)["d"].([]any)[0] = 2
+	actual["untouched"].(map[string]any)["c"] = 2
+
+	assert.Equal(t, map[string]any{"a": map[string]any{"b": 1}, "untouched": map[string]any{"c": 1}}, target)
+	assert.Equal(t, map[string]any{"a": map[string]any{"d": []any{1}}}, patch)
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	original := map[string]any{
+		"title":   "Goodbye!",
+		"author":  map[string]any{"givenName": "John", "familyName": "Doe"},
+		"tags":    []any{"example", "sample"},
+		"content": "This will be unchanged",
+	}
+	modified := map[string]any{
+		"title":       "Hello!",
+		"author":      map[string]any{"givenName": "John"},
+		"tags":        []any{"example"},
+		"content":     "This will be unchanged",
+		"phoneNumber": "+01-123-456-7890",
+	}
+
+	patch := CreateMergePatch(original, modified)
+	assert.Equal(t, map[string]any{
+		"title":       "Hello!",
+		"author":      map[string]any{"familyName": nil},
+		"tags":        []any{"example"},
+		"phoneNumber": "+01-123-456-7890",
+	}, patch)
+	assert.Equal(t, modified, ApplyMergePatch(original, patch))
+	assert.Empty(t, CreateMergePatch(modified, modified))
+}
+
+func TestCreateMergePatch_RoundTrip(t *testing.T) {
+	for _, example := range rfc7386Examples {
+		original, ok1 := decodeJSON[any](t, example.original).(map[string]any)
+		result, ok2 := decodeJSON[any](t, example.result).(map[string]any)
+		if !ok1 || !ok2 {
+			continue
+		}
+		t.Run(example.original+" "+example.result, func(t *testing.T) {
+			patch := CreateMergePatch(original, result)
+			assert.Equal(t, result, ApplyMergePatch(original, patch))
+		})
+	}
+}
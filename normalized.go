@@ -0,0 +1,348 @@
+package maps
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// KeyNormalizer maps a key to its normalized form. Keys with the same normalized
+// form are considered the same key.
+type KeyNormalizer func(key string) string
+
+// LowerCaseNormalizer returns a KeyNormalizer that lower cases keys, suitable for
+// ASCII keys such as HTTP header names.
+func LowerCaseNormalizer() KeyNormalizer {
+	return strings.ToLower
+}
+
+// CaseFoldNormalizer returns a KeyNormalizer that applies Unicode simple case
+// folding, so keys that are equal under strings.EqualFold normalize to the same key.
+func CaseFoldNormalizer() KeyNormalizer {
+	return func(key string) string {
+		return strings.Map(foldRune, key)
+	}
+}
+
+// foldRune returns the smallest rune of the case folding orbit of r, which is the
+// same for every rune of the orbit.
+func foldRune(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+	return folded
+}
+
+// WhitespaceNormalizer returns a KeyNormalizer that trims leading and trailing
+// white space and collapses every other run of white space into a single space.
+func WhitespaceNormalizer() KeyNormalizer {
+	return func(key string) string {
+		return strings.Join(strings.Fields(key), " ")
+	}
+}
+
+// ChainNormalizers returns a KeyNormalizer applying the normalizers in order.
+func ChainNormalizers(normalizers ...KeyNormalizer) KeyNormalizer {
+	return func(key string) string {
+		for _, normalize := range normalizers {
+			key = normalize(key)
+		}
+		return key
+	}
+}
+
+// KeyCollision reports multiple spellings of a key that normalize to the same key.
+type KeyCollision struct {
+	Normalized string
+	// Spellings holds the distinct original spellings in sorted order.
+	Spellings []string
+}
+
+type normalizedEntry[V any] struct {
+	key string
+	val V
+}
+
+// NormalizedMap is a map with string keys that are looked up by their normalized
+// form, such as a case-insensitive map of HTTP headers. The spelling a key was first
+// set with is preserved and returned when iterating.
+//
+// The zero value is not usable, use NewNormalizedMap. A NormalizedMap is not safe
+// for concurrent use.
+type NormalizedMap[V any] struct {
+	normalize KeyNormalizer
+	entries   map[string]normalizedEntry[V]
+}
+
+// NewNormalizedMap creates an empty NormalizedMap using the KeyNormalizer.
+func NewNormalizedMap[V any](normalize KeyNormalizer) *NormalizedMap[V] {
+	return &NormalizedMap[V]{
+		normalize: normalize,
+		entries:   make(map[string]normalizedEntry[V]),
+	}
+}
+
+// NormalizedMapOf creates a NormalizedMap holding the entries of m. If multiple
+// keys of m normalize to the same key, the value of the smallest key in sorted
+// order is kept under its spelling and the collisions are returned.
+func NormalizedMapOf[M ~map[string]V, V any](normalize KeyNormalizer, m M) (*NormalizedMap[V], []KeyCollision) {
+	nm := NewNormalizedMap[V](normalize)
+	c := newCollisionTracker(normalize)
+	for _, k := range sortedStringKeys(m) {
+		c.add(k)
+		nm.SetIfAbsent(k, m[k])
+	}
+	return nm, c.collisions()
+}
+
+// Get returns the value of the key and whether it exists.
+func (m *NormalizedMap[V]) Get(key string) (V, bool) {
+	entry, ok := m.entries[m.normalize(key)]
+	return entry.val, ok
+}
+
+// GetOrDefault returns the value of the key, or defaultVal if it doesn't exist.
+func (m *NormalizedMap[V]) GetOrDefault(key string, defaultVal V) V {
+	if entry, ok := m.entries[m.normalize(key)]; ok {
+		return entry.val
+	}
+	return defaultVal
+}
+
+// Has reports whether the key exists.
+func (m *NormalizedMap[V]) Has(key string) bool {
+	_, ok := m.entries[m.normalize(key)]
+	return ok
+}
+
+// Key returns the spelling the key is stored with and whether it exists.
+func (m *NormalizedMap[V]) Key(key string) (string, bool) {
+	entry, ok := m.entries[m.normalize(key)]
+	return entry.key, ok
+}
+
+// Set sets the value of the key. If the key already exists, possibly with a
+// different spelling, its value is replaced and its original spelling kept.
+func (m *NormalizedMap[V]) Set(key string, val V) {
+	normalized := m.normalize(key)
+	if entry, ok := m.entries[normalized]; ok {
+		key = entry.key
+	}
+	m.entries[normalized] = normalizedEntry[V]{key: key, val: val}
+}
+
+// SetIfAbsent sets the value of the key only if the key doesn't exist, returning
+// true if the value was set.
+func (m *NormalizedMap[V]) SetIfAbsent(key string, val V) bool {
+	normalized := m.normalize(key)
+	if _, ok := m.entries[normalized]; ok {
+		return false
+	}
+	m.entries[normalized] = normalizedEntry[V]{key: key, val: val}
+	return true
+}
+
+// Delete removes the key, returning true if it existed.
+func (m *NormalizedMap[V]) Delete(key string) bool {
+	normalized := m.normalize(key)
+	if _, ok := m.entries[normalized]; !ok {
+		return false
+	}
+	delete(m.entries, normalized)
+	return true
+}
+
+// Len returns the number of keys.
+func (m *NormalizedMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// Keys returns the keys in their original spelling.
+//
+// The keys will be in an indeterminate order.
+func (m *NormalizedMap[V]) Keys() []string {
+	keys := make([]string, 0, len(m.entries))
+	for _, entry := range m.entries {
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// Range calls fn for every key in its original spelling and its value until fn
+// returns false.
+//
+// The keys will be visited in an indeterminate order.
+func (m *NormalizedMap[V]) Range(fn func(key string, val V) bool) {
+	for _, entry := range m.entries {
+		if !fn(entry.key, entry.val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the keys in their original spelling and their
+// values, implementing Map.
+//
+// The entries will be visited in an indeterminate order.
+func (m *NormalizedMap[V]) All() func(yield func(key string, val V) bool) {
+	return m.Range
+}
+
+// Map returns a new map holding the entries keyed by their original spelling.
+func (m *NormalizedMap[V]) Map() map[string]V {
+	res := make(map[string]V, len(m.entries))
+	for _, entry := range m.entries {
+		res[entry.key] = entry.val
+	}
+	return res
+}
+
+// EqualNormalized compares two maps by their normalized keys. The maps are equal if
+// they have the same normalized keys with equal values, which is the case exactly
+// when DiffNormalized finds no differences.
+//
+// If multiple keys normalize to the same key, within a map or across both maps, the
+// collisions are returned. Within a map the value of the smallest key in sorted
+// order is compared.
+func EqualNormalized[M ~map[string]V, V comparable](m1, m2 M, normalize KeyNormalizer) (bool, []KeyCollision) {
+	c := newCollisionTracker(normalize)
+	l := normalizeEntries(m1, c)
+	r := normalizeEntries(m2, c)
+
+	equal := len(l) == len(r)
+	for normalized, entry := range l {
+		if other, ok := r[normalized]; !ok || entry.val != other.val {
+			equal = false
+			break
+		}
+	}
+	return equal, c.collisions()
+}
+
+// DiffNormalized compares two maps by their normalized keys like Diff. The
+// differences are keyed by the spelling of the key in the left map, or in the right
+// map if the key is missing from the left map.
+//
+// If multiple keys normalize to the same key, within a map or across both maps, the
+// collisions are returned. Within a map the value of the smallest key in sorted
+// order is compared.
+func DiffNormalized[M ~map[string]V, V comparable](left M, right M, normalize KeyNormalizer) (map[string]EntryComparison[V], []KeyCollision) {
+	c := newCollisionTracker(normalize)
+	l := normalizeEntries(left, c)
+	r := normalizeEntries(right, c)
+
+	res := make(map[string]EntryComparison[V])
+	for normalized, entry := range l {
+		other, ok := r[normalized]
+		if !ok {
+			res[entry.key] = EntryComparison[V]{Left: entry.val, Reason: DiffMissingRight}
+			continue
+		}
+		if entry.val != other.val {
+			res[entry.key] = EntryComparison[V]{Left: entry.val, Right: other.val, Reason: DiffValue}
+		}
+	}
+	for normalized, entry := range r {
+		if _, ok := l[normalized]; !ok {
+			res[entry.key] = EntryComparison[V]{Right: entry.val, Reason: DiffMissingLeft}
+		}
+	}
+	return res, c.collisions()
+}
+
+// MergeNormalized merges multiple maps by their normalized keys like Merge. Each key
+// keeps the spelling it first appears with, visiting the maps in order. Values of
+// keys normalizing to the same key in different maps are resolved by the
+// ConflictResolver, and the collisions of keys with different spellings are
+// returned.
+//
+// Like EqualNormalized and DiffNormalized, within a map only the value of the
+// smallest key in sorted order is merged.
+func MergeNormalized[M ~map[string]V, V any](normalize KeyNormalizer, fn ConflictResolver[V], src ...M) (map[string]V, []KeyCollision) {
+	c := newCollisionTracker(normalize)
+	merged := make(map[string]normalizedEntry[V])
+	for _, m := range src {
+		for normalized, entry := range normalizeEntries(m, c) {
+			if existing, ok := merged[normalized]; ok {
+				merged[normalized] = normalizedEntry[V]{key: existing.key, val: fn(existing.val, entry.val)}
+			} else {
+				merged[normalized] = entry
+			}
+		}
+	}
+
+	res := make(map[string]V, len(merged))
+	for _, entry := range merged {
+		res[entry.key] = entry.val
+	}
+	return res, c.collisions()
+}
+
+// normalizeEntries indexes the entries of m by their normalized keys, keeping the
+// smallest key in sorted order when keys collide.
+func normalizeEntries[M ~map[string]V, V any](m M, c *collisionTracker) map[string]normalizedEntry[V] {
+	res := make(map[string]normalizedEntry[V], len(m))
+	for _, k := range sortedStringKeys(m) {
+		normalized := c.add(k)
+		if _, ok := res[normalized]; !ok {
+			res[normalized] = normalizedEntry[V]{key: k, val: m[k]}
+		}
+	}
+	return res
+}
+
+func sortedStringKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// collisionTracker records the distinct spellings of every normalized key.
+type collisionTracker struct {
+	normalize KeyNormalizer
+	spellings map[string][]string
+}
+
+func newCollisionTracker(normalize KeyNormalizer) *collisionTracker {
+	return &collisionTracker{normalize: normalize, spellings: make(map[string][]string)}
+}
+
+// add records the key and returns its normalized form.
+func (c *collisionTracker) add(key string) string {
+	normalized := c.normalize(key)
+	if !containsString(c.spellings[normalized], key) {
+		c.spellings[normalized] = append(c.spellings[normalized], key)
+	}
+	return normalized
+}
+
+// collisions returns the normalized keys with multiple spellings, sorted by the
+// normalized key.
+func (c *collisionTracker) collisions() []KeyCollision {
+	var res []KeyCollision
+	for normalized, spellings := range c.spellings {
+		if len(spellings) > 1 {
+			sort.Strings(spellings)
+			res = append(res, KeyCollision{Normalized: normalized, Spellings: spellings})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Normalized < res[j].Normalized
+	})
+	return res
+}
+
+func containsString(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
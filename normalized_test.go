@@ -0,0 +1,212 @@
+package maps
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyNormalizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		normalize KeyNormalizer
+		in        string
+		expected  string
+	}{
+		{name: "Lower Case", normalize: LowerCaseNormalizer(), in: "Content-Type", expected: "content-type"},
+		{name: "Case Fold", normalize: CaseFoldNormalizer(), in: "STRASSE", expected: CaseFoldNormalizer()("strasse")},
+		{name: "Case Fold Kelvin", normalize: CaseFoldNormalizer(), in: "K", expected: CaseFoldNormalizer()("k")},
+		{name: "Whitespace", normalize: WhitespaceNormalizer(), in: "  first \t name\n", expected: "first name"},
+		{
+			name:      "Chain",
+			normalize: ChainNormalizers(WhitespaceNormalizer(), LowerCaseNormalizer()),
+			in:        " User  ID ",
+			expected:  "user id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.normalize(test.in))
+		})
+	}
+}
+
+func TestNormalizedMap(t *testing.T) {
+	headers := NewNormalizedMap[string](LowerCaseNormalizer())
+	headers.Set("Content-Type", "text/plain")
+	headers.Set("content-type", "application/json")
+	assert.True(t, headers.SetIfAbsent("X-Request-ID", "1"))
+	assert.False(t, headers.SetIfAbsent("x-request-id", "2"))
+
+	val, ok := headers.Get("CONTENT-TYPE")
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", val)
+	assert.Equal(t, "none", headers.GetOrDefault("Accept", "none"))
+	assert.True(t, headers.Has("x-REQUEST-id"))
+	assert.Equal(t, 2, headers.Len())
+
+	key, ok := headers.Key("content-type")
+	assert.True(t, ok)
+	assert.Equal(t, "Content-Type", key)
+
+	keys := headers.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"Content-Type", "X-Request-ID"}, keys)
+	assert.Equal(t, map[string]string{"Content-Type": "application/json", "X-Request-ID": "1"}, headers.Map())
+
+	visited := 0
+	headers.Range(func(key string, val string) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 1, visited)
+
+	assert.True(t, headers.Delete("X-REQUEST-ID"))
+	assert.False(t, headers.Delete("X-Request-ID"))
+	assert.Equal(t, 1, headers.Len())
+}
+
+func TestNormalizedMapOf(t *testing.T) {
+	nm, collisions := NormalizedMapOf(LowerCaseNormalizer(), map[string]int{"ID": 1, "id": 2, "Name": 3})
+	assert.Equal(t, map[string]int{"ID": 1, "Name": 3}, nm.Map())
+	assert.Equal(t, []KeyCollision{{Normalized: "id", Spellings: []string{"ID", "id"}}}, collisions)
+
+	_, collisions = NormalizedMapOf(LowerCaseNormalizer(), map[string]int{"ID": 1, "Name": 3})
+	assert.Empty(t, collisions)
+}
+
+func TestEqualNormalized(t *testing.T) {
+	tests := []struct {
+		name       string
+		m1         map[string]string
+		m2         map[string]string
+		expected   bool
+		collisions []KeyCollision
+	}{
+		{
+			name:     "Equal",
+			m1:       map[string]string{"Accept": "a", "Host": "h"},
+			m2:       map[string]string{"accept": "a", "Host": "h"},
+			expected: true,
+			collisions: []KeyCollision{
+				{Normalized: "accept", Spellings: []string{"Accept", "accept"}},
+			},
+		},
+		{
+			name:     "Different Value",
+			m1:       map[string]string{"Accept": "a"},
+			m2:       map[string]string{"Accept": "b"},
+			expected: false,
+		},
+		{
+			name:     "Missing Key",
+			m1:       map[string]string{"Accept": "a", "Host": "h"},
+			m2:       map[string]string{"Accept": "a"},
+			expected: false,
+		},
+		{
+			name:     "Extra Key",
+			m1:       map[string]string{"Accept": "a"},
+			m2:       map[string]string{"Accept": "a", "Host": "h"},
+			expected: false,
+		},
+		{
+			name:     "Agreeing Collision",
+			m1:       map[string]string{"Accept": "a", "accept": "a"},
+			m2:       map[string]string{"ACCEPT": "a"},
+			expected: true,
+			collisions: []KeyCollision{
+				{Normalized: "accept", Spellings: []string{"ACCEPT", "Accept", "accept"}},
+			},
+		},
+		{
+			name:     "Collision Compares Smallest Key",
+			m1:       map[string]string{"Accept": "a", "accept": "b"},
+			m2:       map[string]string{"ACCEPT": "a"},
+			expected: true,
+			collisions: []KeyCollision{
+				{Normalized: "accept", Spellings: []string{"ACCEPT", "Accept", "accept"}},
+			},
+		},
+		{
+			name:     "Collision With Different Smallest Key",
+			m1:       map[string]string{"Accept": "b", "accept": "a"},
+			m2:       map[string]string{"ACCEPT": "a"},
+			expected: false,
+			collisions: []KeyCollision{
+				{Normalized: "accept", Spellings: []string{"ACCEPT", "Accept", "accept"}},
+			},
+		},
+		{
+			name:     "Collision With Same Spelling",
+			m1:       map[string]string{"A": "1", "a": "2"},
+			m2:       map[string]string{"A": "1"},
+			expected: true,
+			collisions: []KeyCollision{
+				{Normalized: "a", Spellings: []string{"A", "a"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			equal, collisions := EqualNormalized(test.m1, test.m2, LowerCaseNormalizer())
+			assert.Equal(t, test.expected, equal)
+			assert.Equal(t, test.collisions, collisions)
+
+			equal, collisions = EqualNormalized(test.m2, test.m1, LowerCaseNormalizer())
+			assert.Equal(t, test.expected, equal)
+			assert.Equal(t, test.collisions, collisions)
+
+			// EqualNormalized agrees with DiffNormalized in both directions.
+			diff, _ := DiffNormalized(test.m1, test.m2, LowerCaseNormalizer())
+			assert.Equal(t, test.expected, len(diff) == 0)
+			diff, _ = DiffNormalized(test.m2, test.m1, LowerCaseNormalizer())
+			assert.Equal(t, test.expected, len(diff) == 0)
+		})
+	}
+}
+
+func TestDiffNormalized(t *testing.T) {
+	left := map[string]int{"User_ID": 1, "Name": 2, "Email": 3, "name": 4}
+	right := map[string]int{"user_id": 1, "NAME": 5, "Phone": 6}
+
+	diff, collisions := DiffNormalized(left, right, LowerCaseNormalizer())
+	assert.Equal(t, map[string]EntryComparison[int]{
+		"Name":  {Left: 2, Right: 5, Reason: DiffValue},
+		"Email": {Left: 3, Reason: DiffMissingRight},
+		"Phone": {Right: 6, Reason: DiffMissingLeft},
+	}, diff)
+	assert.Equal(t, []KeyCollision{
+		{Normalized: "name", Spellings: []string{"NAME", "Name", "name"}},
+		{Normalized: "user_id", Spellings: []string{"User_ID", "user_id"}},
+	}, collisions)
+}
+
+func TestMergeNormalized(t *testing.T) {
+	merged, collisions := MergeNormalized(
+		WhitespaceNormalizer(),
+		SumResolver[int](),
+		map[string]int{"total count": 1, "errors": 2},
+		map[string]int{" total  count ": 3, "warnings": 4},
+		map[string]int{"errors": 5},
+	)
+	assert.Equal(t, map[string]int{"total count": 4, "errors": 7, "warnings": 4}, merged)
+	assert.Equal(t, []KeyCollision{
+		{Normalized: "total count", Spellings: []string{" total  count ", "total count"}},
+	}, collisions)
+
+	// Within a map only the smallest key in sorted order is merged.
+	merged, collisions = MergeNormalized(
+		LowerCaseNormalizer(),
+		SumResolver[int](),
+		map[string]int{"ID": 1, "id": 2},
+		map[string]int{"Id": 3},
+	)
+	assert.Equal(t, map[string]int{"ID": 4}, merged)
+	assert.Equal(t, []KeyCollision{
+		{Normalized: "id", Spellings: []string{"ID", "Id", "id"}},
+	}, collisions)
+}
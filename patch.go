@@ -0,0 +1,181 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Patch is a set of changes between two maps, computed by Diff, that can be
+// replayed onto other maps. Before a change is applied the destination's current
+// value is checked against the value the change expects, the left value of the
+// diff, to detect conflicting modifications.
+//
+// A Patch is immutable and safe to apply to any number of maps.
+type Patch[K, V comparable] struct {
+	changes map[K]EntryComparison[V]
+}
+
+// NewPatch creates a Patch from the result of Diff.
+func NewPatch[K, V comparable](diff map[K]EntryComparison[V]) Patch[K, V] {
+	return Patch[K, V]{changes: Clone(diff)}
+}
+
+// Len returns the number of changes in the Patch.
+func (p Patch[K, V]) Len() int {
+	return len(p.changes)
+}
+
+// Changes returns a copy of the changes in the Patch keyed by the key they apply
+// to.
+func (p Patch[K, V]) Changes() map[K]EntryComparison[V] {
+	return Clone(p.changes)
+}
+
+// Invert returns a Patch that undoes the changes of this Patch. Added entries
+// become removed entries and vice versa, while modified entries swap their left
+// and right values.
+func (p Patch[K, V]) Invert() Patch[K, V] {
+	inverted := make(map[K]EntryComparison[V], len(p.changes))
+	for k, change := range p.changes {
+		reason := change.Reason
+		switch reason {
+		case DiffAdded:
+			reason = DiffRemoved
+		case DiffRemoved:
+			reason = DiffAdded
+		}
+		inverted[k] = EntryComparison[V]{
+			Left:   change.Right,
+			Right:  change.Left,
+			Reason: reason,
+		}
+	}
+	return Patch[K, V]{changes: inverted}
+}
+
+// Apply applies the Patch to dst in strict mode. If the current value of any key
+// in dst doesn't match the value the Patch expects a *PatchConflictError listing
+// every conflict is returned and dst is left unmodified. Changes that have already
+// been applied to dst are not considered conflicts.
+//
+// dst must not be nil if the Patch adds entries.
+func (p Patch[K, V]) Apply(dst map[K]V) error {
+	conflicts := p.conflicts(dst)
+	if len(conflicts) > 0 {
+		return &PatchConflictError[K, V]{Conflicts: conflicts}
+	}
+	p.apply(dst, nil)
+	return nil
+}
+
+// ApplyLenient applies the Patch to dst in lenient mode. Changes that conflict with
+// the current value in dst are skipped and returned while all other changes are
+// applied.
+//
+// dst must not be nil if the Patch adds entries.
+func (p Patch[K, V]) ApplyLenient(dst map[K]V) []PatchConflict[K, V] {
+	conflicts := p.conflicts(dst)
+	skip := make(map[K]struct{}, len(conflicts))
+	for _, conflict := range conflicts {
+		skip[conflict.Key] = struct{}{}
+	}
+	p.apply(dst, skip)
+	return conflicts
+}
+
+// Revert undoes the Patch on dst in strict mode. It is equivalent to calling Apply
+// on the inverted Patch.
+func (p Patch[K, V]) Revert(dst map[K]V) error {
+	return p.Invert().Apply(dst)
+}
+
+// RevertLenient undoes the Patch on dst in lenient mode. It is equivalent to
+// calling ApplyLenient on the inverted Patch.
+func (p Patch[K, V]) RevertLenient(dst map[K]V) []PatchConflict[K, V] {
+	return p.Invert().ApplyLenient(dst)
+}
+
+func (p Patch[K, V]) apply(dst map[K]V, skip map[K]struct{}) {
+	for k, change := range p.changes {
+		if _, ok := skip[k]; ok {
+			continue
+		}
+		if change.Reason == DiffRemoved {
+			delete(dst, k)
+		} else {
+			dst[k] = change.Right
+		}
+	}
+}
+
+// conflicts returns the changes whose expected value doesn't match the current
+// value in dst, sorted by key.
+func (p Patch[K, V]) conflicts(dst map[K]V) []PatchConflict[K, V] {
+	var conflicts []PatchConflict[K, V]
+	for k, change := range p.changes {
+		actual, present := dst[k]
+		var ok bool
+		switch change.Reason {
+		case DiffAdded:
+			ok = !present || actual == change.Right
+		case DiffRemoved:
+			ok = !present || actual == change.Left
+		default:
+			ok = present && (actual == change.Left || actual == change.Right)
+		}
+		if ok {
+			continue
+		}
+		conflicts = append(conflicts, PatchConflict[K, V]{
+			Key:             k,
+			Expected:        change.Left,
+			ExpectedPresent: change.Reason != DiffAdded,
+			Actual:          actual,
+			ActualPresent:   present,
+		})
+	}
+	if len(conflicts) > 1 {
+		sort.Slice(conflicts, func(i, j int) bool {
+			return lessValues(conflicts[i].Key, conflicts[j].Key)
+		})
+	}
+	return conflicts
+}
+
+// PatchConflict describes a change in a Patch that could not be applied because
+// the destination's current value didn't match the value the change expected.
+type PatchConflict[K, V comparable] struct {
+	Key K
+	// Expected is the value the Patch expected the key to hold. It is only
+	// meaningful if ExpectedPresent is true, otherwise the Patch expected the key
+	// to be absent.
+	Expected        V
+	ExpectedPresent bool
+	// Actual is the value the key holds in the destination. It is only meaningful
+	// if ActualPresent is true, otherwise the key is absent.
+	Actual        V
+	ActualPresent bool
+}
+
+// PatchConflictError is returned when a Patch is applied in strict mode and one or
+// more changes conflict with the destination map.
+type PatchConflictError[K, V comparable] struct {
+	Conflicts []PatchConflict[K, V]
+}
+
+func (e *PatchConflictError[K, V]) Error() string {
+	describe := func(v V, present bool) string {
+		if !present {
+			return "<missing>"
+		}
+		return fmt.Sprint(v)
+	}
+
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("%v (expected %s, found %s)",
+			c.Key, describe(c.Expected, c.ExpectedPresent), describe(c.Actual, c.ActualPresent)))
+	}
+	return fmt.Sprintf("patch conflicts on %d keys: %s", len(e.Conflicts), strings.Join(parts, ", "))
+}
@@ -0,0 +1,106 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	left := map[string]int{"red": 1, "blue": 2, "white": 4}
+	right := map[string]int{"red": 1, "blue": 3, "black": 5}
+	patch := NewPatch(Diff(left, right))
+	assert.Equal(t, 3, patch.Len())
+
+	tests := []struct {
+		name      string
+		dst       map[string]int
+		expected  map[string]int
+		conflicts []PatchConflict[string, int]
+	}{
+		{
+			name:     "Matching Destination",
+			dst:      map[string]int{"red": 1, "blue": 2, "white": 4, "green": 7},
+			expected: map[string]int{"red": 1, "blue": 3, "black": 5, "green": 7},
+		},
+		{
+			name:     "Already Applied",
+			dst:      map[string]int{"red": 1, "blue": 3, "black": 5},
+			expected: map[string]int{"red": 1, "blue": 3, "black": 5},
+		},
+		{
+			name:     "Conflicting Destination Is Untouched",
+			dst:      map[string]int{"blue": 9, "white": 4, "black": 6},
+			expected: map[string]int{"blue": 9, "white": 4, "black": 6},
+			conflicts: []PatchConflict[string, int]{
+				{Key: "black", Actual: 6, ActualPresent: true},
+				{Key: "blue", Expected: 2, ExpectedPresent: true, Actual: 9, ActualPresent: true},
+			},
+		},
+		{
+			name:     "Missing Modified Key Conflicts",
+			dst:      map[string]int{"white": 4},
+			expected: map[string]int{"white": 4},
+			conflicts: []PatchConflict[string, int]{
+				{Key: "blue", Expected: 2, ExpectedPresent: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := patch.Apply(test.dst)
+			if test.conflicts == nil {
+				assert.NoError(t, err)
+			} else {
+				var conflictErr *PatchConflictError[string, int]
+				assert.ErrorAs(t, err, &conflictErr)
+				assert.Equal(t, test.conflicts, conflictErr.Conflicts)
+			}
+			assert.Equal(t, test.expected, test.dst)
+		})
+	}
+}
+
+func TestPatch_ApplyLenient(t *testing.T) {
+	left := map[string]int{"red": 1, "blue": 2, "white": 4}
+	right := map[string]int{"red": 1, "blue": 3, "black": 5}
+	patch := NewPatch(Diff(left, right))
+
+	dst := map[string]int{"blue": 9, "white": 4}
+	conflicts := patch.ApplyLenient(dst)
+	assert.Equal(t, []PatchConflict[string, int]{
+		{Key: "blue", Expected: 2, ExpectedPresent: true, Actual: 9, ActualPresent: true},
+	}, conflicts)
+	assert.Equal(t, map[string]int{"blue": 9, "black": 5}, dst)
+}
+
+func TestPatch_RevertAndInvert(t *testing.T) {
+	left := map[string]int{"red": 1, "blue": 2, "white": 4}
+	right := map[string]int{"red": 1, "blue": 3, "black": 5}
+	patch := NewPatch(Diff(left, right))
+
+	dst := Clone(right)
+	assert.NoError(t, patch.Revert(dst))
+	assert.Equal(t, left, dst)
+
+	inverted := patch.Invert()
+	assert.Equal(t, NewPatch(Diff(right, left)).Changes(), inverted.Changes())
+	assert.Equal(t, patch.Changes(), inverted.Invert().Changes())
+
+	dst = map[string]int{"blue": 3, "black": 7}
+	conflicts := patch.RevertLenient(dst)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, "black", conflicts[0].Key)
+	assert.Equal(t, map[string]int{"blue": 2, "black": 7, "white": 4}, dst)
+}
+
+func TestPatchConflictError_Error(t *testing.T) {
+	err := &PatchConflictError[string, int]{
+		Conflicts: []PatchConflict[string, int]{
+			{Key: "black", Actual: 6, ActualPresent: true},
+			{Key: "blue", Expected: 2, ExpectedPresent: true},
+		},
+	}
+	assert.Equal(t, "patch conflicts on 2 keys: black (expected <missing>, found 6), blue (expected 2, found <missing>)", err.Error())
+}
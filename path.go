@@ -0,0 +1,270 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPathNotFound indicates a path references a key or index that doesn't exist.
+	ErrPathNotFound = errors.New("not found")
+	// ErrPathType indicates a path traverses, or resolves to, a value of an
+	// unexpected type.
+	ErrPathType = errors.New("unexpected type")
+	// ErrPathIndex indicates a path segment isn't a valid index of a slice.
+	ErrPathIndex = errors.New("invalid index")
+	// ErrInvalidPath indicates a path couldn't be parsed or can't be used for the
+	// operation, such as setting the document root.
+	ErrInvalidPath = errors.New("invalid path")
+)
+
+// PathError is returned by the path functions such as GetPath and SetPath. It
+// records the full path, the segment that couldn't be resolved and the cause,
+// which wraps one of ErrPathNotFound, ErrPathType, ErrPathIndex or ErrInvalidPath.
+type PathError struct {
+	Path    string
+	Segment string
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	if e.Segment == "" {
+		return fmt.Sprintf("path %q: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("path %q: segment %q: %v", e.Path, e.Segment, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// GetPath returns the value at the path in a nested document such as decoded JSON
+// or YAML, asserted to type T.
+//
+// Paths are either dot separated keys with optional bracketed slice indexes, such
+// as "servers[0].tls.cert", or JSON Pointers (RFC 6901) such as "/servers/0/tls/cert".
+// Slice indexes may also be given as plain segments, as in "servers.0.tls.cert".
+// The empty path references the document itself.
+//
+// A *PathError is returned if the path doesn't exist or the value isn't a T.
+func GetPath[T any](m map[string]any, path string) (T, error) {
+	var zero T
+	tokens, err := parsePath(path)
+	if err != nil {
+		return zero, err
+	}
+
+	var node any = m
+	for _, token := range tokens {
+		if node, err = pathChild(path, node, token); err != nil {
+			return zero, err
+		}
+	}
+
+	val, ok := node.(T)
+	if !ok {
+		segment := ""
+		if len(tokens) > 0 {
+			segment = tokens[len(tokens)-1]
+		}
+		return zero, &PathError{
+			Path:    path,
+			Segment: segment,
+			Err:     fmt.Errorf("%w: expected %v, got %T", ErrPathType, reflect.TypeOf(&zero).Elem(), node),
+		}
+	}
+	return val, nil
+}
+
+// GetPathOrDefault returns the value at the path like GetPath, or returns the
+// default value if the path doesn't exist or the value isn't a T.
+func GetPathOrDefault[T any](m map[string]any, path string, defaultVal T) T {
+	val, err := GetPath[T](m, path)
+	if err != nil {
+		return defaultVal
+	}
+	return val
+}
+
+// HasPath reports whether the path exists in the document. See GetPath for the
+// supported path syntax.
+func HasPath(m map[string]any, path string) bool {
+	_, err := GetPath[any](m, path)
+	return err == nil
+}
+
+// SetPath sets the value at the path, creating intermediate maps for keys that
+// don't exist. A slice index replaces an existing element, while the index one past
+// the last element, or "-" as in JSON Pointer, appends to the slice. See GetPath
+// for the supported path syntax.
+//
+// A *PathError is returned if the path traverses a value that isn't a map or slice,
+// an index is out of range, or the path is empty. Since a nil map can't be set, a
+// *PathError wrapping ErrInvalidPath is returned if the document is nil. The
+// document isn't modified when an error is returned.
+func SetPath(m map[string]any, path string, val any) error {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return &PathError{Path: path, Err: fmt.Errorf("%w: cannot set the document root", ErrInvalidPath)}
+	}
+	if m == nil {
+		return &PathError{Path: path, Err: fmt.Errorf("%w: cannot set a value in a nil document", ErrInvalidPath)}
+	}
+
+	_, err = pathUpdate(path, m, tokens, true, func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			// A nested nil map is replaced, the updated container is stored in its
+			// parent.
+			if container == nil {
+				container = make(map[string]any)
+			}
+			container[token] = val
+			return container, nil
+		case []any:
+			idx, err := pathIndex(path, token, len(container), true)
+			if err != nil {
+				return nil, err
+			}
+			if idx == len(container) {
+				return append(container, val), nil
+			}
+			container[idx] = val
+			return container, nil
+		default:
+			return nil, pathTypeError(path, token, parent)
+		}
+	})
+	return err
+}
+
+// DeletePath removes the value at the path. Deleting a slice element removes it
+// from the slice, shifting the following elements. See GetPath for the supported
+// path syntax.
+//
+// A *PathError wrapping ErrPathNotFound is returned if the path doesn't exist.
+func DeletePath(m map[string]any, path string) error {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return &PathError{Path: path, Err: fmt.Errorf("%w: cannot delete the document root", ErrInvalidPath)}
+	}
+
+	_, err = pathUpdate(path, m, tokens, false, func(parent any, token string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if _, ok := container[token]; !ok {
+				return nil, &PathError{Path: path, Segment: token, Err: ErrPathNotFound}
+			}
+			delete(container, token)
+			return container, nil
+		case []any:
+			idx, err := pathIndex(path, token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			// The capacity is limited so the elements aren't shifted in the backing
+			// array, which may be shared with other slices.
+			return append(container[:idx:idx], container[idx+1:]...), nil
+		default:
+			return nil, pathTypeError(path, token, parent)
+		}
+	})
+	return err
+}
+
+// pathChild returns the child of node referenced by token.
+func pathChild(path string, node any, token string) (any, error) {
+	switch container := node.(type) {
+	case map[string]any:
+		val, ok := container[token]
+		if !ok {
+			return nil, &PathError{Path: path, Segment: token, Err: ErrPathNotFound}
+		}
+		return val, nil
+	case []any:
+		idx, err := pathIndex(path, token, len(container), false)
+		if err != nil {
+			return nil, err
+		}
+		return container[idx], nil
+	default:
+		return nil, pathTypeError(path, token, node)
+	}
+}
+
+// pathUpdate walks to the parent of the value referenced by tokens and replaces the
+// parent with the result of fn, returning the possibly new node. If create is true,
+// missing keys and the index one past the end of a slice are filled with new maps.
+// Containers are only updated once fn succeeded, so an error leaves node unchanged.
+func pathUpdate(path string, node any, tokens []string, create bool, fn func(parent any, token string) (any, error)) (any, error) {
+	token := tokens[0]
+	if len(tokens) == 1 {
+		return fn(node, token)
+	}
+
+	switch container := node.(type) {
+	case map[string]any:
+		child, ok := container[token]
+		if !ok {
+			if !create {
+				return nil, &PathError{Path: path, Segment: token, Err: ErrPathNotFound}
+			}
+			child = make(map[string]any)
+		}
+		updated, err := pathUpdate(path, child, tokens[1:], create, fn)
+		if err != nil {
+			return nil, err
+		}
+		if container == nil {
+			container = make(map[string]any)
+		}
+		container[token] = updated
+		return container, nil
+	case []any:
+		idx, err := pathIndex(path, token, len(container), create)
+		if err != nil {
+			return nil, err
+		}
+		if idx == len(container) {
+			container = append(container, make(map[string]any))
+		}
+		updated, err := pathUpdate(path, container[idx], tokens[1:], create, fn)
+		if err != nil {
+			return nil, err
+		}
+		container[idx] = updated
+		return container, nil
+	default:
+		return nil, pathTypeError(path, token, node)
+	}
+}
+
+// pathIndex parses a slice index of a slice with the given length. If appendable is
+// true the index may also reference the position after the last element, which "-"
+// always refers to.
+func pathIndex(path string, token string, length int, appendable bool) (int, error) {
+	if token == "-" {
+		token = strconv.Itoa(length)
+	}
+	if token == "" || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, &PathError{Path: path, Segment: token, Err: fmt.Errorf("%w: not a slice index", ErrPathIndex)}
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx > length || (idx == length && !appendable) {
+		return 0, &PathError{Path: path, Segment: token, Err: fmt.Errorf("%w: out of range", ErrPathIndex)}
+	}
+	return idx, nil
+}
+
+func pathTypeError(path string, token string, node any) error {
+	return &PathError{Path: path, Segment: token, Err: fmt.Errorf("%w: cannot traverse %T", ErrPathType, node)}
+}
@@ -0,0 +1,279 @@
+package maps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPath(t *testing.T) {
+	doc := map[string]any{
+		"server": map[string]any{
+			"port": 8080,
+			"tls":  map[string]any{"cert": "/etc/cert.pem"},
+		},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b"},
+		},
+		"a/b": "slash",
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected any
+	}{
+		{name: "Dotted", path: "server.tls.cert", expected: "/etc/cert.pem"},
+		{name: "Bracket Index", path: "servers[1].host", expected: "b"},
+		{name: "Dotted Index", path: "servers.0.host", expected: "a"},
+		{name: "JSON Pointer", path: "/servers/1/host", expected: "b"},
+		{name: "JSON Pointer Escape", path: "/a~1b", expected: "slash"},
+		{name: "Map", path: "server.tls", expected: map[string]any{"cert": "/etc/cert.pem"}},
+		{name: "Root", path: "", expected: doc},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			val, err := GetPath[any](doc, test.path)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, val)
+			assert.True(t, HasPath(doc, test.path))
+		})
+	}
+
+	port, err := GetPath[int](doc, "server.port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+}
+
+func TestGetPath_Errors(t *testing.T) {
+	doc := map[string]any{
+		"server": map[string]any{
+			"port": 8080,
+			"tls":  map[string]any{"cert": "/etc/cert.pem"},
+		},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		target  error
+		segment string
+		err     string
+	}{
+		{
+			name:    "Missing Key",
+			path:    "server.tls.key",
+			target:  ErrPathNotFound,
+			segment: "key",
+			err:     `path "server.tls.key": segment "key": not found`,
+		},
+		{
+			name:    "Traverse Scalar",
+			path:    "server.port.number",
+			target:  ErrPathType,
+			segment: "number",
+			err:     `path "server.port.number": segment "number": unexpected type: cannot traverse int`,
+		},
+		{
+			name:    "Wrong Type",
+			path:    "server.tls.cert",
+			target:  ErrPathType,
+			segment: "cert",
+			err:     `path "server.tls.cert": segment "cert": unexpected type: expected int, got string`,
+		},
+		{
+			name:    "Index Out Of Range",
+			path:    "servers[2].host",
+			target:  ErrPathIndex,
+			segment: "2",
+			err:     `path "servers[2].host": segment "2": invalid index: out of range`,
+		},
+		{
+			name:    "Not An Index",
+			path:    "servers.first",
+			target:  ErrPathIndex,
+			segment: "first",
+			err:     `path "servers.first": segment "first": invalid index: not a slice index`,
+		},
+		{
+			name:    "Malformed Path",
+			path:    "servers[0.host",
+			target:  ErrInvalidPath,
+			segment: "servers[0",
+			err:     `path "servers[0.host": segment "servers[0": invalid path: malformed index`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := GetPath[int](doc, test.path)
+			assert.EqualError(t, err, test.err)
+			assert.ErrorIs(t, err, test.target)
+
+			var pathErr *PathError
+			if assert.True(t, errors.As(err, &pathErr)) {
+				assert.Equal(t, test.path, pathErr.Path)
+				assert.Equal(t, test.segment, pathErr.Segment)
+			}
+		})
+	}
+
+	assert.False(t, HasPath(doc, "server.tls.key"))
+}
+
+func TestGetPathOrDefault(t *testing.T) {
+	doc := map[string]any{
+		"server": map[string]any{
+			"port": 8080,
+			"tls":  map[string]any{"cert": "/etc/cert.pem"},
+		},
+	}
+	assert.Equal(t, 8080, GetPathOrDefault(doc, "server.port", 80))
+	assert.Equal(t, 80, GetPathOrDefault(doc, "server.missing", 80))
+	assert.Equal(t, 80, GetPathOrDefault(doc, "server.tls.cert", 80))
+}
+
+func TestSetPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      map[string]any
+		path     string
+		val      any
+		expected map[string]any
+	}{
+		{
+			name:     "Replace",
+			doc:      map[string]any{"server": map[string]any{"port": 8080}},
+			path:     "server.port",
+			val:      9090,
+			expected: map[string]any{"server": map[string]any{"port": 9090}},
+		},
+		{
+			name: "Create Intermediate Maps",
+			doc:  map[string]any{"server": map[string]any{"port": 8080}},
+			path: "server.http.timeouts.read",
+			val:  "5s",
+			expected: map[string]any{"server": map[string]any{
+				"port": 8080,
+				"http": map[string]any{"timeouts": map[string]any{"read": "5s"}},
+			}},
+		},
+		{
+			name:     "Replace Element",
+			doc:      map[string]any{"servers": []any{"a", "b"}},
+			path:     "servers[0]",
+			val:      "x",
+			expected: map[string]any{"servers": []any{"x", "b"}},
+		},
+		{
+			name:     "Append Element",
+			doc:      map[string]any{"servers": []any{"a", "b"}},
+			path:     "/servers/-",
+			val:      "c",
+			expected: map[string]any{"servers": []any{"a", "b", "c"}},
+		},
+		{
+			name:     "Append Element At Length",
+			doc:      map[string]any{"servers": []any{"a", "b"}},
+			path:     "servers.2",
+			val:      "c",
+			expected: map[string]any{"servers": []any{"a", "b", "c"}},
+		},
+		{
+			name: "Create Map In Appended Element",
+			doc: map[string]any{"servers": []any{
+				map[string]any{"host": "a"},
+				map[string]any{"host": "b"},
+			}},
+			path: "servers[2].host",
+			val:  "c",
+			expected: map[string]any{"servers": []any{
+				map[string]any{"host": "a"},
+				map[string]any{"host": "b"},
+				map[string]any{"host": "c"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NoError(t, SetPath(test.doc, test.path, test.val))
+			assert.Equal(t, test.expected, test.doc)
+		})
+	}
+}
+
+func TestSetPath_Errors(t *testing.T) {
+	doc := map[string]any{
+		"server":  map[string]any{"port": 8080},
+		"servers": []any{map[string]any{"host": "a"}, map[string]any{"host": "b"}},
+	}
+
+	err := SetPath(doc, "server.port.number", 1)
+	assert.ErrorIs(t, err, ErrPathType)
+
+	err = SetPath(doc, "servers[5]", 1)
+	assert.ErrorIs(t, err, ErrPathIndex)
+
+	err = SetPath(doc, "", 1)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+
+	err = SetPath(doc, "servers[1].host.name", 1)
+	assert.ErrorIs(t, err, ErrPathType)
+	assert.Equal(t, map[string]any{
+		"server":  map[string]any{"port": 8080},
+		"servers": []any{map[string]any{"host": "a"}, map[string]any{"host": "b"}},
+	}, doc)
+
+	err = SetPath(nil, "a.b", 1)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	assert.EqualError(t, err, `path "a.b": invalid path: cannot set a value in a nil document`)
+}
+
+func TestSetPath_NilNestedMap(t *testing.T) {
+	doc := map[string]any{"a": map[string]any(nil)}
+	assert.NoError(t, SetPath(doc, "a.b.c", 1))
+	assert.Equal(t, map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}}, doc)
+}
+
+func TestDeletePath(t *testing.T) {
+	doc := map[string]any{
+		"server": map[string]any{
+			"port": 8080,
+			"tls":  map[string]any{"cert": "/etc/cert.pem"},
+		},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b"},
+		},
+		"a/b": "slash",
+	}
+
+	assert.NoError(t, DeletePath(doc, "server.tls.cert"))
+	assert.Equal(t, map[string]any{}, doc["server"].(map[string]any)["tls"])
+
+	assert.NoError(t, DeletePath(doc, "servers[0]"))
+	assert.Equal(t, []any{map[string]any{"host": "b"}}, doc["servers"])
+
+	assert.NoError(t, DeletePath(doc, "/a~1b"))
+	assert.False(t, HasPath(doc, "/a~1b"))
+
+	assert.ErrorIs(t, DeletePath(doc, "server.tls.cert"), ErrPathNotFound)
+	assert.ErrorIs(t, DeletePath(doc, "missing.key"), ErrPathNotFound)
+	assert.ErrorIs(t, DeletePath(doc, "servers[1]"), ErrPathIndex)
+	assert.ErrorIs(t, DeletePath(doc, ""), ErrInvalidPath)
+
+	// Deleting an element doesn't modify other slices sharing the backing array.
+	items := []any{1, 2, 3}
+	doc = map[string]any{"items": items}
+	assert.NoError(t, DeletePath(doc, "items[0]"))
+	assert.Equal(t, []any{2, 3}, doc["items"])
+	assert.Equal(t, []any{1, 2, 3}, items)
+}
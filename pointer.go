@@ -0,0 +1,108 @@
+package maps
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// escapePointerToken escapes a single reference token per RFC 6901 so it can be
+// safely joined into a JSON Pointer.
+func escapePointerToken(token string) string {
+	return pointerEscaper.Replace(token)
+}
+
+// unescapePointerToken reverses escapePointerToken.
+func unescapePointerToken(token string) string {
+	return pointerUnescaper.Replace(token)
+}
+
+// joinPointer appends a reference token to a JSON Pointer.
+func joinPointer(pointer string, token string) string {
+	return pointer + "/" + escapePointerToken(token)
+}
+
+// pointerOf joins unescaped reference tokens into a JSON Pointer.
+func pointerOf(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(escapePointerToken(token))
+	}
+	return sb.String()
+}
+
+// splitPointer splits a JSON Pointer into its unescaped reference tokens. The
+// empty pointer, which references the whole document, has no tokens.
+func splitPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = unescapePointerToken(token)
+	}
+	return tokens
+}
+
+// splitPath splits a path into its reference tokens like parsePath. A path that
+// can't be parsed, such as one with an unterminated bracket, is split on dots.
+func splitPath(path string) []string {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return strings.Split(path, ".")
+	}
+	return tokens
+}
+
+// parsePath parses a path into its reference tokens. Paths starting with "/" are
+// JSON Pointers, all other paths are dot separated keys with optional bracketed
+// indexes such as "spec.containers[0].image". The empty path has no tokens.
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(path, "/") {
+		return splitPointer(path), nil
+	}
+
+	var tokens []string
+	for _, part := range strings.Split(path, ".") {
+		open := strings.IndexByte(part, '[')
+		if open < 0 {
+			tokens = append(tokens, part)
+			continue
+		}
+		if open > 0 {
+			tokens = append(tokens, part[:open])
+		}
+		for rest := part[open:]; rest != ""; {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, &PathError{Path: path, Segment: part, Err: fmt.Errorf("%w: malformed index", ErrInvalidPath)}
+			}
+			tokens = append(tokens, rest[1:end])
+			rest = rest[end+1:]
+		}
+	}
+	return tokens, nil
+}
+
+// matchPointer reports whether the pointer matches the pattern. Patterns are JSON
+// Pointers where a "*" token matches any single token. When prefix is true the
+// pattern also matches every pointer nested beneath it.
+func matchPointer(pattern []string, pointer []string, prefix bool) bool {
+	if len(pointer) < len(pattern) || (!prefix && len(pointer) != len(pattern)) {
+		return false
+	}
+	for i, token := range pattern {
+		if token != "*" && token != pointer[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package maps
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NamedMap is a map paired with the name of the source it was loaded from, such as
+// "defaults", "config.yaml" or "env".
+type NamedMap[K comparable, V any] struct {
+	Name string
+	Map  map[K]V
+}
+
+// Provenance records where the merged value of a key came from.
+type Provenance struct {
+	// Source is the name of the source whose value won.
+	Source string
+	// Overridden lists, in merge order, the other sources that held the key and
+	// whose values were discarded.
+	Overridden []string
+}
+
+// MergeTracked merges multiple named maps into a single new map like Merge and
+// additionally returns the Provenance of every key in the merged map.
+//
+// The winning source of a conflict is determined by comparing the value returned
+// by the ConflictResolver against both conflicting values with reflect.DeepEqual.
+// If the resolver combines the values into a new value, such as SumResolver does,
+// the later source is recorded as the winner.
+func MergeTracked[K comparable, V any](fn ConflictResolver[V], sources ...NamedMap[K, V]) (map[K]V, map[K]Provenance) {
+	merged := make(map[K]V)
+	provenance := make(map[K]Provenance)
+	for _, src := range sources {
+		for k, v := range src.Map {
+			existing, ok := merged[k]
+			if !ok {
+				merged[k] = v
+				provenance[k] = Provenance{Source: src.Name}
+				continue
+			}
+
+			newVal := fn(existing, v)
+			merged[k] = newVal
+			p := provenance[k]
+			if reflect.DeepEqual(newVal, v) || !reflect.DeepEqual(newVal, existing) {
+				p.Overridden = append(p.Overridden, p.Source)
+				p.Source = src.Name
+			} else {
+				p.Overridden = append(p.Overridden, src.Name)
+			}
+			provenance[k] = p
+		}
+	}
+	return merged, provenance
+}
+
+// FormatProvenance renders the result of MergeTracked as an annotated dump sorted by
+// key, intended for debugging where merged values came from, for example:
+//
+//	port = 8080 (from env, overrides defaults, config.yaml)
+//	timeout = 30s (from defaults)
+func FormatProvenance[K comparable, V any](merged map[K]V, provenance map[K]Provenance) string {
+	keys := Keys(merged)
+	sortKeys(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%v = %v", k, merged[k])
+		if p, ok := provenance[k]; ok {
+			fmt.Fprintf(&sb, " (from %s", p.Source)
+			if len(p.Overridden) > 0 {
+				fmt.Fprintf(&sb, ", overrides %s", strings.Join(p.Overridden, ", "))
+			}
+			sb.WriteByte(')')
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
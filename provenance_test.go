@@ -0,0 +1,93 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTracked(t *testing.T) {
+	defaults := NamedMap[string, string]{Name: "defaults", Map: map[string]string{
+		"host":    "localhost",
+		"port":    "8080",
+		"timeout": "30s",
+	}}
+	file := NamedMap[string, string]{Name: "config.yaml", Map: map[string]string{
+		"host": "0.0.0.0",
+		"port": "9090",
+	}}
+	env := NamedMap[string, string]{Name: "env", Map: map[string]string{
+		"port":  "10000",
+		"debug": "true",
+	}}
+
+	tests := []struct {
+		name       string
+		resolver   ConflictResolver[string]
+		merged     map[string]string
+		provenance map[string]Provenance
+	}{
+		{
+			name:     "Overwrite Resolver",
+			resolver: OverwriteResolver[string](),
+			merged: map[string]string{
+				"host":    "0.0.0.0",
+				"port":    "10000",
+				"timeout": "30s",
+				"debug":   "true",
+			},
+			provenance: map[string]Provenance{
+				"host":    {Source: "config.yaml", Overridden: []string{"defaults"}},
+				"port":    {Source: "env", Overridden: []string{"defaults", "config.yaml"}},
+				"timeout": {Source: "defaults"},
+				"debug":   {Source: "env"},
+			},
+		},
+		{
+			name:     "Nop Resolver",
+			resolver: NopResolver[string](),
+			merged: map[string]string{
+				"host":    "localhost",
+				"port":    "8080",
+				"timeout": "30s",
+				"debug":   "true",
+			},
+			provenance: map[string]Provenance{
+				"host":    {Source: "defaults", Overridden: []string{"config.yaml"}},
+				"port":    {Source: "defaults", Overridden: []string{"config.yaml", "env"}},
+				"timeout": {Source: "defaults"},
+				"debug":   {Source: "env"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, provenance := MergeTracked(test.resolver, defaults, file, env)
+			assert.Equal(t, test.merged, merged)
+			assert.Equal(t, test.provenance, provenance)
+			assert.Equal(t, Merge(test.resolver, defaults.Map, file.Map, env.Map), merged)
+		})
+	}
+}
+
+func TestMergeTracked_CombinedValues(t *testing.T) {
+	merged, provenance := MergeTracked(SumResolver[int](),
+		NamedMap[string, int]{Name: "a", Map: map[string]int{"requests": 1}},
+		NamedMap[string, int]{Name: "b", Map: map[string]int{"requests": 2}},
+	)
+	assert.Equal(t, map[string]int{"requests": 3}, merged)
+	assert.Equal(t, map[string]Provenance{"requests": {Source: "b", Overridden: []string{"a"}}}, provenance)
+}
+
+func TestFormatProvenance(t *testing.T) {
+	merged, provenance := MergeTracked(OverwriteResolver[int](),
+		NamedMap[string, int]{Name: "defaults", Map: map[string]int{"port": 8080, "workers": 4}},
+		NamedMap[string, int]{Name: "config.yaml", Map: map[string]int{"port": 9090}},
+		NamedMap[string, int]{Name: "env", Map: map[string]int{"port": 10000}},
+	)
+
+	expected := "port = 10000 (from env, overrides defaults, config.yaml)\n" +
+		"workers = 4 (from defaults)\n"
+	assert.Equal(t, expected, FormatProvenance(merged, provenance))
+}
@@ -0,0 +1,771 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Match is a value selected by a Query together with its concrete location in the
+// document, as a JSON Pointer (RFC 6901).
+type Match struct {
+	Path  string
+	Value any
+}
+
+// QuerySyntaxError is returned by CompileQuery when an expression can't be parsed.
+type QuerySyntaxError struct {
+	Query  string
+	Offset int
+	Msg    string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("query %q: offset %d: %s", e.Query, e.Offset, e.Msg)
+}
+
+// Query is a compiled JSONPath-style expression that selects values from nested
+// documents such as decoded JSON or YAML. A Query is safe for concurrent use.
+//
+// The supported syntax is:
+//
+//	$                 the document root, optional at the start of a query
+//	.name ['name']    the member name of a map
+//	.* [*]            all members of a map or elements of a slice
+//	..                recursive descent, applies the following selector to every
+//	                  nested value, as in $..name or $..[0]
+//	[0] [-1]          an element of a slice, negative indexes count from the end
+//	[start:end:step]  a slice of a slice, all parts are optional
+//	['a','b'] [0,2]   a union of names or indexes
+//	[?(expr)]         the members or elements for which the filter is true
+//
+// Filter expressions compare values using ==, !=, <, <=, > and >=, combined with
+// &&, || and ! and grouped with parentheses. Operands are string, number, true,
+// false and null literals, or paths relative to the current value (@.status) or the
+// root ($.limits.max) that select a single value using names and indexes only. An
+// operand on its own tests that the path exists, as in [?(@.labels)].
+type Query struct {
+	expr  string
+	steps []queryStep
+}
+
+// CompileQuery parses a query expression, returning a *QuerySyntaxError if it is
+// malformed. The leading "$" may be omitted, so "spec.containers[*].image" and
+// "$.spec.containers[*].image" are equivalent.
+func CompileQuery(expr string) (*Query, error) {
+	p := queryParser{expr: expr}
+	steps, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return &Query{expr: expr, steps: steps}, nil
+}
+
+// MustCompileQuery is like CompileQuery but panics if the expression is malformed.
+// It simplifies initialization of global variables holding compiled queries.
+func MustCompileQuery(expr string) *Query {
+	q, err := CompileQuery(expr)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Select compiles the query expression and returns the matching values of the
+// document. Use CompileQuery to reuse a query across documents.
+func Select(doc map[string]any, expr string) ([]Match, error) {
+	q, err := CompileQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	return q.Select(doc), nil
+}
+
+// String returns the source expression of the query.
+func (q *Query) String() string {
+	return q.expr
+}
+
+// Select returns the values of the document matched by the query. Members of maps
+// are visited in sorted key order and elements of slices in index order, so the
+// result is deterministic. The values are not copied and share memory with the
+// document.
+func (q *Query) Select(doc map[string]any) []Match {
+	nodes := []queryNode{{value: doc}}
+	for _, step := range q.steps {
+		var next []queryNode
+		for _, node := range nodes {
+			if !step.recursive {
+				next = step.apply(node, doc, next)
+				continue
+			}
+			for _, desc := range descendants(node, nil) {
+				next = step.apply(desc, doc, next)
+			}
+		}
+		nodes = next
+	}
+
+	matches := make([]Match, len(nodes))
+	for i, node := range nodes {
+		matches[i] = Match{Path: pointerOf(node.path), Value: node.value}
+	}
+	return matches
+}
+
+// Values returns only the values of the document matched by the query.
+func (q *Query) Values(doc map[string]any) []any {
+	matches := q.Select(doc)
+	values := make([]any, len(matches))
+	for i, match := range matches {
+		values[i] = match.Value
+	}
+	return values
+}
+
+type queryNode struct {
+	path  []string
+	value any
+}
+
+func (n queryNode) child(token string, value any) queryNode {
+	path := make([]string, len(n.path)+1)
+	copy(path, n.path)
+	path[len(n.path)] = token
+	return queryNode{path: path, value: value}
+}
+
+// children returns the members of a map in sorted key order or the elements of a
+// slice. Other values have no children.
+func (n queryNode) children() []queryNode {
+	switch container := n.value.(type) {
+	case map[string]any:
+		keys := Keys(container)
+		sort.Strings(keys)
+		res := make([]queryNode, len(keys))
+		for i, k := range keys {
+			res[i] = n.child(k, container[k])
+		}
+		return res
+	case []any:
+		res := make([]queryNode, len(container))
+		for i, elem := range container {
+			res[i] = n.child(strconv.Itoa(i), elem)
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+// descendants appends the node and all values nested beneath it in pre-order.
+func descendants(n queryNode, out []queryNode) []queryNode {
+	out = append(out, n)
+	for _, child := range n.children() {
+		out = descendants(child, out)
+	}
+	return out
+}
+
+type queryStep struct {
+	recursive bool
+	selectors []querySelector
+}
+
+func (s queryStep) apply(node queryNode, root any, out []queryNode) []queryNode {
+	for _, sel := range s.selectors {
+		out = sel.apply(node, root, out)
+	}
+	return out
+}
+
+type selectorKind int
+
+const (
+	selectName selectorKind = iota
+	selectIndex
+	selectSlice
+	selectWildcard
+	selectFilter
+)
+
+type querySelector struct {
+	kind  selectorKind
+	name  string
+	index int
+	// start, end and step of a slice, nil if omitted.
+	start, end, step *int
+	filter           filterExpr
+}
+
+func (s querySelector) apply(node queryNode, root any, out []queryNode) []queryNode {
+	switch s.kind {
+	case selectName:
+		if m, ok := node.value.(map[string]any); ok {
+			if val, ok := m[s.name]; ok {
+				out = append(out, node.child(s.name, val))
+			}
+		}
+	case selectIndex:
+		if arr, ok := node.value.([]any); ok {
+			idx := s.index
+			if idx < 0 {
+				idx += len(arr)
+			}
+			if idx >= 0 && idx < len(arr) {
+				out = append(out, node.child(strconv.Itoa(idx), arr[idx]))
+			}
+		}
+	case selectSlice:
+		if arr, ok := node.value.([]any); ok {
+			for _, idx := range s.sliceIndexes(len(arr)) {
+				out = append(out, node.child(strconv.Itoa(idx), arr[idx]))
+			}
+		}
+	case selectWildcard:
+		out = append(out, node.children()...)
+	case selectFilter:
+		for _, child := range node.children() {
+			if s.filter.eval(child.value, root) {
+				out = append(out, child)
+			}
+		}
+	}
+	return out
+}
+
+// sliceIndexes returns the indexes selected by a slice of a slice with the given
+// length, following the semantics of Python slices.
+func (s querySelector) sliceIndexes(length int) []int {
+	step := 1
+	if s.step != nil {
+		step = *s.step
+	}
+	if step == 0 {
+		return nil
+	}
+
+	normalize := func(i *int, def, lower, upper int) int {
+		if i == nil {
+			return def
+		}
+		idx := *i
+		if idx < 0 {
+			idx += length
+		}
+		if idx < lower {
+			return lower
+		}
+		if idx > upper {
+			return upper
+		}
+		return idx
+	}
+
+	var res []int
+	if step > 0 {
+		start := normalize(s.start, 0, 0, length)
+		end := normalize(s.end, length, 0, length)
+		for i := start; i < end; i += step {
+			res = append(res, i)
+		}
+	} else {
+		start := normalize(s.start, length-1, -1, length-1)
+		end := normalize(s.end, -1, -1, length-1)
+		for i := start; i > end; i += step {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// filterExpr is a boolean expression evaluated against the current value of a
+// filter selector.
+type filterExpr interface {
+	eval(current, root any) bool
+}
+
+type filterOr struct{ left, right filterExpr }
+
+func (f filterOr) eval(current, root any) bool {
+	return f.left.eval(current, root) || f.right.eval(current, root)
+}
+
+type filterAnd struct{ left, right filterExpr }
+
+func (f filterAnd) eval(current, root any) bool {
+	return f.left.eval(current, root) && f.right.eval(current, root)
+}
+
+type filterNot struct{ expr filterExpr }
+
+func (f filterNot) eval(current, root any) bool {
+	return !f.expr.eval(current, root)
+}
+
+type filterExists struct{ operand filterOperand }
+
+func (f filterExists) eval(current, root any) bool {
+	_, ok := f.operand.resolve(current, root)
+	return ok
+}
+
+type filterCompare struct {
+	op          string
+	left, right filterOperand
+}
+
+func (f filterCompare) eval(current, root any) bool {
+	l, lok := f.left.resolve(current, root)
+	r, rok := f.right.resolve(current, root)
+	switch f.op {
+	case "==":
+		return filterEqual(l, lok, r, rok)
+	case "!=":
+		return !filterEqual(l, lok, r, rok)
+	case "<":
+		return lok && rok && filterLess(l, r)
+	case ">":
+		return lok && rok && filterLess(r, l)
+	case "<=":
+		return lok && rok && (filterLess(l, r) || jsonEqual(l, r))
+	case ">=":
+		return lok && rok && (filterLess(r, l) || jsonEqual(l, r))
+	default:
+		return false
+	}
+}
+
+// filterEqual reports whether two operands are equal, where two missing values are
+// equal to each other but not to any value.
+func filterEqual(l any, lok bool, r any, rok bool) bool {
+	if !lok || !rok {
+		return lok == rok
+	}
+	return jsonEqual(l, r)
+}
+
+// filterLess orders numbers numerically and strings lexically. Values of other or
+// mismatched types are not ordered.
+func filterLess(l, r any) bool {
+	if ln, ok := jsonNumber(l); ok {
+		rn, ok := jsonNumber(r)
+		return ok && ln < rn
+	}
+	if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		return ok && ls < rs
+	}
+	return false
+}
+
+// filterOperand is a literal or a path selecting a single value relative to the
+// current value (@) or the document root ($).
+type filterOperand struct {
+	literal   any
+	isLiteral bool
+	root      bool
+	path      []querySelector
+}
+
+func (o filterOperand) resolve(current, root any) (any, bool) {
+	if o.isLiteral {
+		return o.literal, true
+	}
+	node := queryNode{value: current}
+	if o.root {
+		node.value = root
+	}
+	for _, sel := range o.path {
+		res := sel.apply(node, root, nil)
+		if len(res) != 1 {
+			return nil, false
+		}
+		node = res[0]
+	}
+	return node.value, true
+}
+
+type queryParser struct {
+	expr string
+	pos  int
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return &QuerySyntaxError{Query: p.expr, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *queryParser) eof() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *queryParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.expr[p.pos]
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\n' || p.peek() == '\r') {
+		p.pos++
+	}
+}
+
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expect(s string) error {
+	p.skipSpace()
+	if !p.consume(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *queryParser) parseName() (string, error) {
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected a member name")
+	}
+	return p.expr[start:p.pos], nil
+}
+
+func (p *queryParser) parseQuery() ([]queryStep, error) {
+	var steps []queryStep
+	p.skipSpace()
+	if !p.consume("$") && !p.eof() && p.peek() != '.' && p.peek() != '[' {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, queryStep{selectors: []querySelector{{kind: selectName, name: name}}})
+	}
+
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return steps, nil
+		}
+
+		var step queryStep
+		switch {
+		case p.consume(".."):
+			step.recursive = true
+			if p.peek() == '[' {
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				step.selectors = selectors
+				break
+			}
+			sel, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			step.selectors = []querySelector{sel}
+		case p.consume("."):
+			sel, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			step.selectors = []querySelector{sel}
+		case p.peek() == '[':
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			step.selectors = selectors
+		default:
+			return nil, p.errorf("unexpected character %q", p.peek())
+		}
+		steps = append(steps, step)
+	}
+}
+
+func (p *queryParser) parseDotSelector() (querySelector, error) {
+	if p.consume("*") {
+		return querySelector{kind: selectWildcard}, nil
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return querySelector{}, err
+	}
+	return querySelector{kind: selectName, name: name}, nil
+}
+
+// parseBracket parses a bracketed selector or union of selectors.
+func (p *queryParser) parseBracket() ([]querySelector, error) {
+	p.consume("[")
+	p.skipSpace()
+	if p.consume("?") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return []querySelector{{kind: selectFilter, filter: expr}}, nil
+	}
+
+	var selectors []querySelector
+	for {
+		p.skipSpace()
+		sel, err := p.parseBracketSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected \",\" or \"]\"")
+		}
+	}
+}
+
+func (p *queryParser) parseBracketSelector() (querySelector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return querySelector{kind: selectWildcard}, nil
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return querySelector{}, err
+		}
+		return querySelector{kind: selectName, name: name}, nil
+	}
+
+	// An index or a slice, where each part of a slice is optional.
+	var parts [3]*int
+	n := 0
+	for {
+		p.skipSpace()
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			i, err := p.parseInt()
+			if err != nil {
+				return querySelector{}, err
+			}
+			parts[n] = &i
+		}
+		p.skipSpace()
+		if n == 2 || !p.consume(":") {
+			break
+		}
+		n++
+	}
+
+	if n == 0 {
+		if parts[0] == nil {
+			return querySelector{}, p.errorf("expected a selector")
+		}
+		return querySelector{kind: selectIndex, index: *parts[0]}, nil
+	}
+	return querySelector{kind: selectSlice, start: parts[0], end: parts[1], step: parts[2]}, nil
+}
+
+func (p *queryParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	i, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	return i, nil
+}
+
+func (p *queryParser) parseString() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && !p.eof():
+			esc := p.peek()
+			p.pos++
+			switch esc {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(esc)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *queryParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseUnary() (filterExpr, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !strings.HasPrefix(p.expr[p.pos:], "!=") {
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{expr: expr}, nil
+	}
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return filterCompare{op: op, left: left, right: right}, nil
+		}
+	}
+	if left.isLiteral {
+		return nil, p.errorf("expected a comparison operator")
+	}
+	return filterExists{operand: left}, nil
+}
+
+func (p *queryParser) parseOperand() (filterOperand, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		return p.parseOperandPath(filterOperand{root: c == '$'})
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		return filterOperand{literal: s, isLiteral: true}, err
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for !p.eof() && strings.IndexByte("+-.0123456789eE", p.peek()) >= 0 {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return filterOperand{}, p.errorf("invalid number")
+		}
+		return filterOperand{literal: f, isLiteral: true}, nil
+	case p.consume("true"):
+		return filterOperand{literal: true, isLiteral: true}, nil
+	case p.consume("false"):
+		return filterOperand{literal: false, isLiteral: true}, nil
+	case p.consume("null"):
+		return filterOperand{literal: nil, isLiteral: true}, nil
+	default:
+		return filterOperand{}, p.errorf("expected an operand")
+	}
+}
+
+// parseOperandPath parses the names and indexes following @ or $ in a filter.
+func (p *queryParser) parseOperandPath(operand filterOperand) (filterOperand, error) {
+	for {
+		switch {
+		case p.consume("."):
+			name, err := p.parseName()
+			if err != nil {
+				return filterOperand{}, err
+			}
+			operand.path = append(operand.path, querySelector{kind: selectName, name: name})
+		case p.peek() == '[':
+			p.pos++
+			p.skipSpace()
+			if c := p.peek(); c == '\'' || c == '"' {
+				name, err := p.parseString()
+				if err != nil {
+					return filterOperand{}, err
+				}
+				operand.path = append(operand.path, querySelector{kind: selectName, name: name})
+			} else {
+				i, err := p.parseInt()
+				if err != nil {
+					return filterOperand{}, err
+				}
+				operand.path = append(operand.path, querySelector{kind: selectIndex, index: i})
+			}
+			if err := p.expect("]"); err != nil {
+				return filterOperand{}, err
+			}
+		default:
+			return operand, nil
+		}
+	}
+}
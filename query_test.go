@@ -0,0 +1,175 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const queryDocument = `{
+	"spec": {
+		"containers": [
+			{"name": "app", "image": "app:1.2", "status": "running", "restarts": 0},
+			{"name": "sidecar", "image": "envoy:1.27", "status": "failed", "restarts": 3},
+			{"name": "init", "image": "busybox", "status": "failed", "restarts": 1, "labels": {"tier": "init"}}
+		],
+		"limits": {"restarts": 2}
+	},
+	"jobs": {
+		"backup": {"status": "failed"},
+		"report": {"status": "done"}
+	},
+	"my key": "spaced"
+}`
+
+func TestQuery_Select(t *testing.T) {
+	doc := decodeJSON[map[string]any](t, queryDocument)
+
+	tests := []struct {
+		name  string
+		query string
+		paths []string
+	}{
+		{
+			name:  "Child Without Root",
+			query: "spec.limits.restarts",
+			paths: []string{"/spec/limits/restarts"},
+		},
+		{
+			name:  "Wildcard",
+			query: "$.spec.containers[*].image",
+			paths: []string{"/spec/containers/0/image", "/spec/containers/1/image", "/spec/containers/2/image"},
+		},
+		{
+			name:  "Dot Wildcard Over Map",
+			query: "$.jobs.*.status",
+			paths: []string{"/jobs/backup/status", "/jobs/report/status"},
+		},
+		{
+			name:  "Bracket Names",
+			query: "$['my key']",
+			paths: []string{"/my key"},
+		},
+		{
+			name:  "Negative Index",
+			query: "$.spec.containers[-1].name",
+			paths: []string{"/spec/containers/2/name"},
+		},
+		{
+			name:  "Union",
+			query: `$.spec.containers[0,2]["name","image"]`,
+			paths: []string{
+				"/spec/containers/0/name", "/spec/containers/0/image",
+				"/spec/containers/2/name", "/spec/containers/2/image",
+			},
+		},
+		{
+			name:  "Slice",
+			query: "$.spec.containers[1:].name",
+			paths: []string{"/spec/containers/1/name", "/spec/containers/2/name"},
+		},
+		{
+			name:  "Reverse Slice",
+			query: "$.spec.containers[::-2].name",
+			paths: []string{"/spec/containers/2/name", "/spec/containers/0/name"},
+		},
+		{
+			name:  "Recursive Descent",
+			query: "$..status",
+			paths: []string{
+				"/jobs/backup/status", "/jobs/report/status",
+				"/spec/containers/0/status", "/spec/containers/1/status", "/spec/containers/2/status",
+			},
+		},
+		{
+			name:  "Recursive Filter",
+			query: `$..[?(@.status == "failed")]`,
+			paths: []string{"/jobs/backup", "/spec/containers/1", "/spec/containers/2"},
+		},
+		{
+			name:  "Filter Comparison",
+			query: "$.spec.containers[?@.restarts > 0 && @.restarts <= 1].name",
+			paths: []string{"/spec/containers/2/name"},
+		},
+		{
+			name:  "Filter Root Reference",
+			query: "$.spec.containers[?(@.restarts >= $.spec.limits.restarts)].name",
+			paths: []string{"/spec/containers/1/name"},
+		},
+		{
+			name:  "Filter Or Not",
+			query: `$.spec.containers[?(!(@.status == 'failed') || @.name == "init")].name`,
+			paths: []string{"/spec/containers/0/name", "/spec/containers/2/name"},
+		},
+		{
+			name:  "Filter Existence",
+			query: "$.spec.containers[?(@.labels)].name",
+			paths: []string{"/spec/containers/2/name"},
+		},
+		{
+			name:  "Filter Nested Path",
+			query: "$.spec.containers[?(@['labels'].tier == 'init')].name",
+			paths: []string{"/spec/containers/2/name"},
+		},
+		{
+			name:  "No Match",
+			query: "$.spec.volumes[*]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := CompileQuery(test.query)
+			assert.NoError(t, err)
+			assert.Equal(t, test.query, q.String())
+
+			var paths []string
+			for _, match := range q.Select(doc) {
+				paths = append(paths, match.Path)
+				val, err := GetPath[any](doc, match.Path)
+				assert.NoError(t, err)
+				assert.Equal(t, val, match.Value)
+			}
+			assert.Equal(t, test.paths, paths)
+		})
+	}
+}
+
+func TestQuery_Values(t *testing.T) {
+	doc := decodeJSON[map[string]any](t, queryDocument)
+
+	q := MustCompileQuery(`$.spec.containers[?(@.status != "failed")].image`)
+	assert.Equal(t, []any{"app:1.2"}, q.Values(doc))
+
+	matches, err := Select(doc, "$..limits")
+	assert.NoError(t, err)
+	assert.Equal(t, []Match{{Path: "/spec/limits", Value: map[string]any{"restarts": float64(2)}}}, matches)
+}
+
+func TestCompileQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: "$.", err: `query "$.": offset 2: expected a member name`},
+		{query: "$.a[", err: `query "$.a[": offset 4: expected a selector`},
+		{query: "$.a[0", err: `query "$.a[0": offset 5: expected "," or "]"`},
+		{query: "$['a", err: `query "$['a": offset 4: unterminated string`},
+		{query: "$[?(@.a == )]", err: `query "$[?(@.a == )]": offset 11: expected an operand`},
+		{query: "$[?(@.a == 1]", err: `query "$[?(@.a == 1]": offset 12: expected ")"`},
+		{query: "$[?('a')]", err: `query "$[?('a')]": offset 7: expected a comparison operator`},
+		{query: "$ foo", err: `query "$ foo": offset 2: unexpected character 'f'`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := CompileQuery(test.query)
+			assert.Nil(t, q)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+
+	assert.Panics(t, func() {
+		MustCompileQuery("$[")
+	})
+}
@@ -0,0 +1,191 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// DiffSummary holds the number of entries added, removed and modified in a diff.
+type DiffSummary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+}
+
+// String returns the summary as a single line, for example
+// "1 added, 2 removed, 3 modified".
+func (s DiffSummary) String() string {
+	return fmt.Sprintf("%d added, %d removed, %d modified", s.Added, s.Removed, s.Modified)
+}
+
+// SummarizeDiff counts the entries added, removed and modified in the diff returned
+// by Diff. Entries whose type changed are counted as modified.
+func SummarizeDiff[K, V comparable](diff map[K]EntryComparison[V]) DiffSummary {
+	var summary DiffSummary
+	for _, entry := range diff {
+		switch entry.Reason {
+		case DiffAdded:
+			summary.Added++
+		case DiffRemoved:
+			summary.Removed++
+		default:
+			summary.Modified++
+		}
+	}
+	return summary
+}
+
+// FormatOption configures how FormatDiff renders a diff.
+type FormatOption func(opts *formatOptions)
+
+type formatOptions struct {
+	color bool
+}
+
+// WithColor colors the lines rendered by FormatDiff with ANSI escape codes: red for
+// removed, green for added and yellow for modified entries.
+func WithColor() FormatOption {
+	return func(opts *formatOptions) {
+		opts.color = true
+	}
+}
+
+// FormatDiff renders the diff returned by Diff as a unified text report sorted by
+// key, followed by a summary line. Each entry is rendered on its own line prefixed
+// with a marker: "+" for added entries, "-" for removed entries and "~" for
+// modified entries, for example:
+//
+//	~ blue: 2 -> 1
+//	+ red: 4
+//	- white: 4
+//
+//	1 added, 1 removed, 1 modified
+func FormatDiff[K, V comparable](diff map[K]EntryComparison[V], opts ...FormatOption) string {
+	var options formatOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var sb strings.Builder
+	for _, key := range sortedDiffKeys(diff) {
+		entry := diff[key]
+		var line, color string
+		switch entry.Reason {
+		case DiffAdded:
+			line = fmt.Sprintf("+ %v: %v", key, entry.Right)
+			color = ansiGreen
+		case DiffRemoved:
+			line = fmt.Sprintf("- %v: %v", key, entry.Left)
+			color = ansiRed
+		default:
+			line = fmt.Sprintf("~ %v: %v -> %v", key, entry.Left, entry.Right)
+			color = ansiYellow
+		}
+		if options.color {
+			line = color + line + ansiReset
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	if len(diff) > 0 {
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(SummarizeDiff(diff).String())
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+type jsonDiff[K, V comparable] struct {
+	Summary DiffSummary           `json:"summary"`
+	Entries []jsonDiffEntry[K, V] `json:"entries"`
+}
+
+type jsonDiffEntry[K, V comparable] struct {
+	Key    K          `json:"key"`
+	Reason DiffReason `json:"reason"`
+	Left   *V         `json:"left,omitempty"`
+	Right  *V         `json:"right,omitempty"`
+}
+
+// FormatDiffJSON renders the diff returned by Diff as a JSON document holding a
+// summary and the entries sorted by key. The left value is omitted for added
+// entries and the right value is omitted for removed entries.
+//
+//	{
+//	  "summary": {"added": 1, "removed": 0, "modified": 1},
+//	  "entries": [
+//	    {"key": "blue", "reason": "modified", "left": 2, "right": 1},
+//	    {"key": "red", "reason": "added", "right": 4}
+//	  ]
+//	}
+func FormatDiffJSON[K, V comparable](diff map[K]EntryComparison[V]) ([]byte, error) {
+	doc := jsonDiff[K, V]{
+		Summary: SummarizeDiff(diff),
+		Entries: make([]jsonDiffEntry[K, V], 0, len(diff)),
+	}
+	for _, key := range sortedDiffKeys(diff) {
+		entry := diff[key]
+		jsonEntry := jsonDiffEntry[K, V]{
+			Key:    key,
+			Reason: entry.Reason,
+		}
+		if entry.Reason != DiffAdded {
+			jsonEntry.Left = &entry.Left
+		}
+		if entry.Reason != DiffRemoved {
+			jsonEntry.Right = &entry.Right
+		}
+		doc.Entries = append(doc.Entries, jsonEntry)
+	}
+	return json.Marshal(doc)
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// FormatDiffMarkdown renders the diff returned by Diff as a Markdown table sorted by
+// key, followed by a summary line. The output is suitable for pull request and
+// issue comments.
+func FormatDiffMarkdown[K, V comparable](diff map[K]EntryComparison[V]) string {
+	var sb strings.Builder
+	sb.WriteString("| | Key | Left | Right |\n")
+	sb.WriteString("|---|---|---|---|\n")
+	for _, key := range sortedDiffKeys(diff) {
+		entry := diff[key]
+		var marker, left, right string
+		switch entry.Reason {
+		case DiffAdded:
+			marker = "+"
+			right = fmt.Sprint(entry.Right)
+		case DiffRemoved:
+			marker = "-"
+			left = fmt.Sprint(entry.Left)
+		default:
+			marker = "~"
+			left = fmt.Sprint(entry.Left)
+			right = fmt.Sprint(entry.Right)
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n",
+			marker,
+			markdownEscaper.Replace(fmt.Sprint(key)),
+			markdownEscaper.Replace(left),
+			markdownEscaper.Replace(right))
+	}
+	sb.WriteString("\n**")
+	sb.WriteString(SummarizeDiff(diff).String())
+	sb.WriteString("**\n")
+	return sb.String()
+}
+
+func sortedDiffKeys[K, V comparable](diff map[K]EntryComparison[V]) []K {
+	keys := Keys(diff)
+	sortKeys(keys)
+	return keys
+}
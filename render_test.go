@@ -0,0 +1,156 @@
+package maps
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffReason_String(t *testing.T) {
+	var _ fmt.Stringer = DiffValue
+	var _ encoding.TextMarshaler = DiffValue
+
+	tests := []struct {
+		reason   DiffReason
+		expected string
+	}{
+		{reason: DiffValue, expected: "modified"},
+		{reason: DiffMissingLeft, expected: "added"},
+		{reason: DiffMissingRight, expected: "removed"},
+		{reason: DiffTypeChanged, expected: "type-changed"},
+		{reason: DiffReason(42), expected: "DiffReason(42)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.reason.String())
+		})
+	}
+}
+
+func TestDiffReason_MarshalText(t *testing.T) {
+	for _, reason := range []DiffReason{DiffModified, DiffAdded, DiffRemoved, DiffTypeChanged} {
+		text, err := reason.MarshalText()
+		assert.NoError(t, err)
+
+		var actual DiffReason
+		assert.NoError(t, actual.UnmarshalText(text))
+		assert.Equal(t, reason, actual)
+	}
+
+	_, err := DiffReason(42).MarshalText()
+	assert.Error(t, err)
+
+	var reason DiffReason
+	assert.Error(t, reason.UnmarshalText([]byte("renamed")))
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	summary := SummarizeDiff(map[string]EntryComparison[int]{
+		"blue":  {Left: 2, Right: 1, Reason: DiffModified},
+		"red":   {Right: 4, Reason: DiffAdded},
+		"white": {Left: 4, Reason: DiffRemoved},
+	})
+	assert.Equal(t, DiffSummary{Added: 1, Removed: 1, Modified: 1}, summary)
+	assert.Equal(t, "1 added, 1 removed, 1 modified", summary.String())
+}
+
+func TestFormatDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		diff     map[string]EntryComparison[int]
+		opts     []FormatOption
+		expected string
+	}{
+		{
+			name:     "No Differences",
+			diff:     map[string]EntryComparison[int]{},
+			expected: "0 added, 0 removed, 0 modified\n",
+		},
+		{
+			name: "Plain",
+			diff: map[string]EntryComparison[int]{
+				"blue":  {Left: 2, Right: 1, Reason: DiffModified},
+				"red":   {Right: 4, Reason: DiffAdded},
+				"white": {Left: 4, Reason: DiffRemoved},
+			},
+			expected: "~ blue: 2 -> 1\n" +
+				"+ red: 4\n" +
+				"- white: 4\n" +
+				"\n" +
+				"1 added, 1 removed, 1 modified\n",
+		},
+		{
+			name: "Color",
+			diff: map[string]EntryComparison[int]{
+				"blue":  {Left: 2, Right: 1, Reason: DiffModified},
+				"red":   {Right: 4, Reason: DiffAdded},
+				"white": {Left: 4, Reason: DiffRemoved},
+			},
+			opts: []FormatOption{WithColor()},
+			expected: "\x1b[33m~ blue: 2 -> 1\x1b[0m\n" +
+				"\x1b[32m+ red: 4\x1b[0m\n" +
+				"\x1b[31m- white: 4\x1b[0m\n" +
+				"\n" +
+				"1 added, 1 removed, 1 modified\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, FormatDiff(test.diff, test.opts...))
+		})
+	}
+}
+
+func TestFormatDiff_SortsNumericKeys(t *testing.T) {
+	diff := Diff(map[int]string{}, map[int]string{10: "ten", 2: "two", 1: "one"})
+	assert.Equal(t, "+ 1: one\n+ 2: two\n+ 10: ten\n\n3 added, 0 removed, 0 modified\n", FormatDiff(diff))
+}
+
+func TestFormatDiffJSON(t *testing.T) {
+	actual, err := FormatDiffJSON(map[string]EntryComparison[int]{
+		"blue":  {Left: 2, Right: 1, Reason: DiffModified},
+		"red":   {Right: 4, Reason: DiffAdded},
+		"white": {Left: 4, Reason: DiffRemoved},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"summary": {"added": 1, "removed": 1, "modified": 1},
+		"entries": [
+			{"key": "blue", "reason": "modified", "left": 2, "right": 1},
+			{"key": "red", "reason": "added", "right": 4},
+			{"key": "white", "reason": "removed", "left": 4}
+		]
+	}`, string(actual))
+
+	var decoded struct {
+		Entries []struct {
+			Reason DiffReason `json:"reason"`
+		} `json:"entries"`
+	}
+	assert.NoError(t, json.Unmarshal(actual, &decoded))
+	assert.Equal(t, DiffAdded, decoded.Entries[1].Reason)
+}
+
+func TestFormatDiffMarkdown(t *testing.T) {
+	diff := map[string]EntryComparison[int]{
+		"a|b":   {Left: 1, Right: 2, Reason: DiffModified},
+		"blue":  {Left: 2, Right: 1, Reason: DiffModified},
+		"red":   {Right: 4, Reason: DiffAdded},
+		"white": {Left: 4, Reason: DiffRemoved},
+	}
+
+	expected := "| | Key | Left | Right |\n" +
+		"|---|---|---|---|\n" +
+		"| ~ | a\\|b | 1 | 2 |\n" +
+		"| ~ | blue | 2 | 1 |\n" +
+		"| + | red |  | 4 |\n" +
+		"| - | white | 4 |  |\n" +
+		"\n" +
+		"**1 added, 1 removed, 2 modified**\n"
+	assert.Equal(t, expected, FormatDiffMarkdown(diff))
+}
@@ -0,0 +1,311 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaType is the type of value a Schema accepts.
+type SchemaType int
+
+const (
+	// TypeAny accepts values of any type, including nil.
+	TypeAny SchemaType = iota
+	// TypeString accepts strings.
+	TypeString
+	// TypeNumber accepts numbers of any Go type, including json.Number.
+	TypeNumber
+	// TypeInteger accepts numbers with an integral value, so the float64 80 decoded
+	// from JSON is accepted but 80.5 isn't.
+	TypeInteger
+	// TypeBool accepts bools.
+	TypeBool
+	// TypeObject accepts map[string]any values.
+	TypeObject
+	// TypeArray accepts slices and arrays.
+	TypeArray
+)
+
+func (t SchemaType) String() string {
+	switch t {
+	case TypeAny:
+		return "any"
+	case TypeString:
+		return "string"
+	case TypeNumber:
+		return "number"
+	case TypeInteger:
+		return "integer"
+	case TypeBool:
+		return "bool"
+	case TypeObject:
+		return "object"
+	case TypeArray:
+		return "array"
+	default:
+		return "SchemaType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Schema describes the structure of a map[string]any document, such as decoded JSON
+// or YAML, declared in Go code. The zero value accepts any value. A Schema must not
+// be modified while it is used.
+//
+//	schema := &maps.Schema{
+//		Type: maps.TypeObject,
+//		Properties: map[string]*maps.Schema{
+//			"host": {Type: maps.TypeString, Required: true},
+//			"port": {Type: maps.TypeInteger, Minimum: &minPort, Default: 8080},
+//			"mode": {Type: maps.TypeString, Enum: []any{"dev", "prod"}},
+//		},
+//	}
+type Schema struct {
+	// Type is the type of value accepted.
+	Type SchemaType
+	// Required marks a property of an object as required.
+	Required bool
+	// Default is the value ApplyDefaults sets for a missing property.
+	Default any
+	// Enum, if not empty, lists the accepted values. Values are compared using JSON
+	// semantics, so numbers of different Go types are equal if their values are.
+	Enum []any
+	// Minimum and Maximum, if not nil, are the inclusive bounds of numbers.
+	Minimum *float64
+	Maximum *float64
+	// MinLength and MaxLength bound the number of characters of strings and the
+	// number of elements of arrays. A MaxLength of zero means no limit.
+	MinLength int
+	MaxLength int
+	// Pattern, if not nil, must match strings.
+	Pattern *regexp.Regexp
+	// Properties declares the keys of an object and their schemas.
+	Properties map[string]*Schema
+	// AdditionalKeys allows an object to hold keys not declared in Properties. It
+	// is ignored if Properties is nil, in which case any key is accepted.
+	AdditionalKeys bool
+	// Items is the schema of the elements of an array.
+	Items *Schema
+	// Check, if not nil, performs custom validation of the value. The returned
+	// error's message is reported as a violation.
+	Check func(v any) error
+}
+
+// Violation is a single failure of a document to satisfy a Schema.
+type Violation struct {
+	// Path is the JSON Pointer (RFC 6901) of the offending value.
+	Path string
+	// Message describes the failure.
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError is returned by Schema.Validate and lists every violation found,
+// ordered by path.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return fmt.Sprintf("%d schema violation(s): %s", len(e.Violations), strings.Join(msgs, "; "))
+}
+
+// Validate validates the document against the schema, which is expected to describe
+// an object. Every violation is collected rather than stopping at the first, and if
+// there are any they are returned as a *ValidationError.
+func (s *Schema) Validate(m map[string]any) error {
+	var violations []Violation
+	s.validate("", m, &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+func (s *Schema) validate(path string, v any, violations *[]Violation) {
+	report := func(format string, args ...any) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !s.Type.accepts(v) {
+		report("expected %s, got %T", s.Type, v)
+		return
+	}
+
+	if len(s.Enum) > 0 && !containsValue(s.Enum, v) {
+		report("must be one of %v", s.Enum)
+	}
+	if n, ok := jsonNumber(v); ok {
+		if s.Minimum != nil && n < *s.Minimum {
+			report("must be >= %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			report("must be <= %v", *s.Maximum)
+		}
+	}
+	if str, ok := v.(string); ok {
+		s.validateLength(utf8.RuneCountInString(str), report)
+		if s.Pattern != nil && !s.Pattern.MatchString(str) {
+			report("must match pattern %s", s.Pattern)
+		}
+	}
+	if s.Check != nil {
+		if err := s.Check(v); err != nil {
+			report("%s", err)
+		}
+	}
+
+	if obj, ok := v.(map[string]any); ok {
+		s.validateObject(path, obj, violations)
+	}
+	if arr := reflect.ValueOf(v); isSchemaArray(arr) {
+		s.validateLength(arr.Len(), report)
+		if s.Items != nil {
+			for i := 0; i < arr.Len(); i++ {
+				s.Items.validate(joinPointer(path, strconv.Itoa(i)), arr.Index(i).Interface(), violations)
+			}
+		}
+	}
+}
+
+func (s *Schema) validateLength(length int, report func(format string, args ...any)) {
+	if length < s.MinLength {
+		report("length must be at least %d", s.MinLength)
+	}
+	if s.MaxLength > 0 && length > s.MaxLength {
+		report("length must be at most %d", s.MaxLength)
+	}
+}
+
+func (s *Schema) validateObject(path string, obj map[string]any, violations *[]Violation) {
+	if s.Properties == nil {
+		return
+	}
+
+	keys := Keys(s.Properties)
+	for k := range obj {
+		if _, ok := s.Properties[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := joinPointer(path, k)
+		prop, declared := s.Properties[k]
+		val, present := obj[k]
+		switch {
+		case !declared && !s.AdditionalKeys:
+			*violations = append(*violations, Violation{Path: childPath, Message: "unexpected key"})
+		case !declared:
+		case !present && prop.Required:
+			*violations = append(*violations, Violation{Path: childPath, Message: "required key is missing"})
+		case present:
+			prop.validate(childPath, val, violations)
+		}
+	}
+}
+
+// ApplyDefaults sets the declared Default of every property missing from the
+// document, recursing into nested objects and the objects of arrays. Defaults are
+// deep copied so the document doesn't share memory with the schema. A missing
+// nested object is created if any of its properties has a default, so the defaults
+// are visible to GetPath and the typed getters.
+//
+// Since a nil map can't be set, a nil document, or a nil map nested in it, is left
+// unchanged.
+func (s *Schema) ApplyDefaults(m map[string]any) {
+	s.applyDefaults(m)
+}
+
+func (s *Schema) applyDefaults(v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		if val == nil {
+			return
+		}
+		for k, prop := range s.Properties {
+			child, ok := val[k]
+			if !ok {
+				if prop.Default != nil {
+					val[k] = deepCopyValue(prop.Default)
+				} else if prop.hasDefaults() {
+					obj := make(map[string]any)
+					prop.applyDefaults(obj)
+					val[k] = obj
+				}
+				continue
+			}
+			prop.applyDefaults(child)
+		}
+	case []any:
+		if s.Items != nil {
+			for _, elem := range val {
+				s.Items.applyDefaults(elem)
+			}
+		}
+	}
+}
+
+// hasDefaults reports whether an object schema declares a default for any of its
+// properties, directly or nested.
+func (s *Schema) hasDefaults() bool {
+	for _, prop := range s.Properties {
+		if prop.Default != nil || prop.hasDefaults() {
+			return true
+		}
+	}
+	return false
+}
+
+func (t SchemaType) accepts(v any) bool {
+	switch t {
+	case TypeAny:
+		return true
+	case TypeString:
+		_, ok := v.(string)
+		return ok
+	case TypeNumber:
+		_, ok := jsonNumber(v)
+		return ok
+	case TypeInteger:
+		n, ok := jsonNumber(v)
+		return ok && n == math.Trunc(n)
+	case TypeBool:
+		_, ok := v.(bool)
+		return ok
+	case TypeObject:
+		_, ok := v.(map[string]any)
+		return ok
+	case TypeArray:
+		return isSchemaArray(reflect.ValueOf(v))
+	default:
+		return false
+	}
+}
+
+func isSchemaArray(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,227 @@
+package maps
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	minPort, maxPort := 1.0, 65535.0
+	schema := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"name": {Type: TypeString, Required: true, Pattern: regexp.MustCompile(`^[a-z-]+$`), MaxLength: 10},
+			"mode": {Type: TypeString, Enum: []any{"dev", "prod"}, Default: "dev"},
+			"server": {
+				Type: TypeObject,
+				Properties: map[string]*Schema{
+					"host": {Type: TypeString, Default: "localhost"},
+					"port": {Type: TypeInteger, Minimum: &minPort, Maximum: &maxPort, Default: 8080},
+					"tls":  {Type: TypeBool},
+				},
+			},
+			"ratio":  {Type: TypeNumber},
+			"labels": {Type: TypeObject, AdditionalKeys: true, Properties: map[string]*Schema{}},
+			"routes": {
+				Type:      TypeArray,
+				MinLength: 1,
+				Items: &Schema{
+					Type: TypeObject,
+					Properties: map[string]*Schema{
+						"path":    {Type: TypeString, Required: true},
+						"methods": {Type: TypeArray, Items: &Schema{Type: TypeString}, Default: []any{"GET"}},
+					},
+				},
+			},
+			"owner": {Check: func(v any) error {
+				if s, ok := v.(string); ok && !strings.Contains(s, "@") {
+					return errors.New("must be an email address")
+				}
+				return nil
+			}},
+		},
+	}
+
+	valid := decodeJSON[map[string]any](t, `{
+		"name": "api",
+		"mode": "prod",
+		"server": {"host": "0.0.0.0", "port": 443, "tls": true},
+		"ratio": 0.5,
+		"labels": {"team": "a"},
+		"routes": [{"path": "/", "methods": ["GET", "POST"]}],
+		"owner": "ops@example.com"
+	}`)
+	assert.NoError(t, schema.Validate(valid))
+
+	invalid := decodeJSON[map[string]any](t, `{
+		"mode": "test",
+		"server": {"port": 80.5, "tls": "yes", "debug": true},
+		"ratio": "high",
+		"routes": [{"methods": ["GET", 1]}, {"path": "/x"}],
+		"owner": "ops",
+		"extra": 1
+	}`)
+	err := schema.Validate(invalid)
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, []Violation{
+			{Path: "/extra", Message: "unexpected key"},
+			{Path: "/mode", Message: "must be one of [dev prod]"},
+			{Path: "/name", Message: "required key is missing"},
+			{Path: "/owner", Message: "must be an email address"},
+			{Path: "/ratio", Message: "expected number, got string"},
+			{Path: "/routes/0/methods/1", Message: "expected string, got float64"},
+			{Path: "/routes/0/path", Message: "required key is missing"},
+			{Path: "/server/debug", Message: "unexpected key"},
+			{Path: "/server/port", Message: "expected integer, got float64"},
+			{Path: "/server/tls", Message: "expected bool, got string"},
+		}, validationErr.Violations)
+	}
+}
+
+func TestSchema_ValidateConstraints(t *testing.T) {
+	maxPort := 65535.0
+
+	tests := []struct {
+		name     string
+		schema   *Schema
+		doc      map[string]any
+		expected []Violation
+	}{
+		{
+			name: "Pattern And Length",
+			schema: &Schema{Type: TypeObject, Properties: map[string]*Schema{
+				"name": {Type: TypeString, Pattern: regexp.MustCompile(`^[a-z-]+$`), MaxLength: 10},
+			}},
+			doc: map[string]any{"name": "Not Valid Name"},
+			expected: []Violation{
+				{Path: "/name", Message: "length must be at most 10"},
+				{Path: "/name", Message: "must match pattern ^[a-z-]+$"},
+			},
+		},
+		{
+			name: "Range",
+			schema: &Schema{Type: TypeObject, Properties: map[string]*Schema{
+				"server": {Type: TypeObject, Properties: map[string]*Schema{
+					"port": {Type: TypeInteger, Maximum: &maxPort},
+				}},
+			}},
+			doc: map[string]any{"server": map[string]any{"port": 70000}},
+			expected: []Violation{
+				{Path: "/server/port", Message: "must be <= 65535"},
+			},
+		},
+		{
+			name: "Array Length",
+			schema: &Schema{Type: TypeObject, Properties: map[string]*Schema{
+				"routes": {Type: TypeArray, MinLength: 1},
+			}},
+			doc: map[string]any{"routes": []any{}},
+			expected: []Violation{
+				{Path: "/routes", Message: "length must be at least 1"},
+			},
+		},
+		{
+			name: "Typed Slices",
+			schema: &Schema{Type: TypeObject, Properties: map[string]*Schema{
+				"routes": {Type: TypeArray, Items: &Schema{Type: TypeObject, Properties: map[string]*Schema{
+					"path": {Type: TypeString, Required: true},
+				}}},
+			}},
+			doc: map[string]any{"routes": []map[string]any{{"path": "/"}}},
+		},
+		{
+			name:   "Unexpected Key",
+			schema: &Schema{Type: TypeObject, Properties: map[string]*Schema{"name": {Type: TypeString}}},
+			doc:    map[string]any{"extra": 1, "name": "api"},
+			expected: []Violation{
+				{Path: "/extra", Message: "unexpected key"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.schema.Validate(test.doc)
+			if test.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, &ValidationError{Violations: test.expected}, err)
+		})
+	}
+
+	err := (&Schema{Type: TypeObject, Properties: map[string]*Schema{}}).Validate(map[string]any{"extra": 1})
+	assert.EqualError(t, err, "1 schema violation(s): /extra: unexpected key")
+}
+
+func TestSchema_ApplyDefaults(t *testing.T) {
+	schema := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"name": {Type: TypeString, Required: true},
+			"mode": {Type: TypeString, Enum: []any{"dev", "prod"}, Default: "dev"},
+			"server": {
+				Type: TypeObject,
+				Properties: map[string]*Schema{
+					"host": {Type: TypeString, Default: "localhost"},
+					"port": {Type: TypeInteger, Default: 8080},
+					"tls":  {Type: TypeBool},
+				},
+			},
+			"routes": {
+				Type: TypeArray,
+				Items: &Schema{
+					Type: TypeObject,
+					Properties: map[string]*Schema{
+						"path":    {Type: TypeString, Required: true},
+						"methods": {Type: TypeArray, Items: &Schema{Type: TypeString}, Default: []any{"GET"}},
+					},
+				},
+			},
+		},
+	}
+
+	doc := map[string]any{
+		"name":   "api",
+		"routes": []any{map[string]any{"path": "/"}, map[string]any{"path": "/x", "methods": []any{"PUT"}}},
+	}
+	schema.ApplyDefaults(doc)
+	assert.Equal(t, map[string]any{
+		"name":   "api",
+		"mode":   "dev",
+		"server": map[string]any{"host": "localhost", "port": 8080},
+		"routes": []any{
+			map[string]any{"path": "/", "methods": []any{"GET"}},
+			map[string]any{"path": "/x", "methods": []any{"PUT"}},
+		},
+	}, doc)
+	assert.NoError(t, schema.Validate(doc))
+	assert.Equal(t, 8080, GetIntOrDefault(doc["server"].(map[string]any), "port", 0))
+
+	// Defaults are copied rather than shared with the schema.
+	doc["routes"].([]any)[0].(map[string]any)["methods"].([]any)[0] = "DELETE"
+	assert.Equal(t, []any{"GET"}, schema.Properties["routes"].Items.Properties["methods"].Default)
+
+	// Existing values are kept.
+	doc = map[string]any{"mode": "prod", "server": map[string]any{"port": 9090}}
+	schema.ApplyDefaults(doc)
+	assert.Equal(t, "prod", doc["mode"])
+	assert.Equal(t, map[string]any{"host": "localhost", "port": 9090}, doc["server"])
+
+	// Nil maps are left unchanged.
+	assert.NotPanics(t, func() { schema.ApplyDefaults(nil) })
+	doc = map[string]any{"mode": "prod", "server": map[string]any(nil)}
+	schema.ApplyDefaults(doc)
+	assert.Equal(t, map[string]any{"mode": "prod", "server": map[string]any(nil)}, doc)
+}
+
+func TestSchemaType_String(t *testing.T) {
+	assert.Equal(t, "integer", TypeInteger.String())
+	assert.Equal(t, "SchemaType(42)", SchemaType(42).String())
+}
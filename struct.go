@@ -0,0 +1,497 @@
+package maps
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// StructOptions configures FromStruct and ToStruct. The zero value uses the "map"
+// and "json" tags and converts values strictly.
+type StructOptions struct {
+	// TagName is the struct tag naming fields. By default the "map" tag is used,
+	// falling back to the "json" tag. Fields without a tag use the field name.
+	TagName string
+	// WeaklyTyped enables ToStruct to coerce values between types, such as parsing
+	// the string "8080" into an int, "30s" into a time.Duration or "true" into a
+	// bool, and formatting numbers and bools into strings.
+	WeaklyTyped bool
+	// ErrorUnused causes ToStruct to return a *StructKeysError if the map holds keys
+	// that don't match any field.
+	ErrorUnused bool
+	// ErrorUnset causes ToStruct to return a *StructKeysError if struct fields have
+	// no matching key in the map.
+	ErrorUnset bool
+	// Metadata, if not nil, is populated by ToStruct with the keys that were
+	// decoded, unused and unset. It allows reporting unknown keys as warnings
+	// without failing.
+	Metadata *StructMetadata
+}
+
+// StructMetadata describes the keys processed by ToStruct. All keys are JSON
+// Pointers (RFC 6901) relative to the root map, sorted.
+type StructMetadata struct {
+	// Keys holds the keys that were decoded into a field.
+	Keys []string
+	// Unused holds the keys that didn't match any field.
+	Unused []string
+	// Unset holds the fields that had no matching key, named by their key.
+	Unset []string
+}
+
+// StructKeysError is returned by ToStruct when StructOptions.ErrorUnused or
+// StructOptions.ErrorUnset is enabled and keys are unused or fields unset. The keys
+// are JSON Pointers (RFC 6901) relative to the root map.
+type StructKeysError struct {
+	Unused []string
+	Unset  []string
+}
+
+func (e *StructKeysError) Error() string {
+	var parts []string
+	if len(e.Unused) > 0 {
+		parts = append(parts, "unused keys: "+strings.Join(e.Unused, ", "))
+	}
+	if len(e.Unset) > 0 {
+		parts = append(parts, "unset fields: "+strings.Join(e.Unset, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FromStruct converts a struct, or a pointer to a struct, into a map[string]any
+// suitable for the generic helpers of this package such as Merge, Diff and
+// DeepMerge.
+//
+// Fields are named by their tag, see StructOptions.TagName, and support the tag
+// options "omitempty", which omits zero values, and "inline", which promotes the
+// fields of a nested struct into the parent map. Embedded structs without a tag name
+// are inlined as well. Fields tagged "-" and unexported fields are skipped.
+//
+// Nested structs are converted to map[string]any, maps with string keys to
+// map[string]any and slices and arrays to []any, recursively. Structs implementing
+// encoding.TextMarshaler, such as time.Time, and all other values are kept as is.
+func FromStruct(v any, opts StructOptions) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("expected a struct, got nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %T", v)
+	}
+	return structToMap(rv, opts), nil
+}
+
+func structToMap(v reflect.Value, opts StructOptions) map[string]any {
+	fields := cachedStructFields(v.Type(), opts.TagName)
+	res := make(map[string]any, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index, false)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		res[f.name] = toDocumentValue(fv, opts)
+	}
+	return res
+}
+
+func toDocumentValue(v reflect.Value, opts StructOptions) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toDocumentValue(v.Elem(), opts)
+	case reflect.Struct:
+		if v.Type().Implements(textMarshalerType) {
+			return v.Interface()
+		}
+		return structToMap(v, opts)
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		res := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			res[iter.Key().String()] = toDocumentValue(iter.Value(), opts)
+		}
+		return res
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		res := make([]any, v.Len())
+		for i := range res {
+			res[i] = toDocumentValue(v.Index(i), opts)
+		}
+		return res
+	default:
+		return v.Interface()
+	}
+}
+
+// ToStruct decodes a map into the struct pointed to by target, the inverse of
+// FromStruct. Keys are matched to fields by name, see FromStruct, falling back to a
+// case-insensitive match. Fields without a matching key keep their current value,
+// so target can be pre-populated with defaults.
+//
+// Nested maps decode into nested structs, maps and pointers, and slices into slices
+// and arrays. Numbers convert between types as long as their value is preserved,
+// strings decode into types implementing encoding.TextUnmarshaler, such as
+// time.Time, and StructOptions.WeaklyTyped enables further coercion. A
+// *ConversionError is returned for values that can't be converted, in which case
+// target may have been partially decoded.
+//
+// Keys without a matching field and fields without a matching key are reported
+// through StructOptions.Metadata, or as a *StructKeysError if StructOptions.ErrorUnused
+// or StructOptions.ErrorUnset is enabled.
+func ToStruct(m map[string]any, target any, opts StructOptions) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	d := structDecoder{opts: opts}
+	if err := d.decodeStruct("", m, rv.Elem()); err != nil {
+		return err
+	}
+	sort.Strings(d.keys)
+	sort.Strings(d.unused)
+	sort.Strings(d.unset)
+
+	if opts.Metadata != nil {
+		*opts.Metadata = StructMetadata{Keys: d.keys, Unused: d.unused, Unset: d.unset}
+	}
+	keysErr := &StructKeysError{}
+	if opts.ErrorUnused {
+		keysErr.Unused = d.unused
+	}
+	if opts.ErrorUnset {
+		keysErr.Unset = d.unset
+	}
+	if len(keysErr.Unused) > 0 || len(keysErr.Unset) > 0 {
+		return keysErr
+	}
+	return nil
+}
+
+type structDecoder struct {
+	opts   StructOptions
+	keys   []string
+	unused []string
+	unset  []string
+}
+
+func (d *structDecoder) decodeStruct(path string, m map[string]any, v reflect.Value) error {
+	used := make(map[string]bool, len(m))
+	for _, f := range cachedStructFields(v.Type(), d.opts.TagName) {
+		key, ok := lookupFieldKey(m, f.name, used)
+		if !ok {
+			d.unset = append(d.unset, joinPointer(path, f.name))
+			continue
+		}
+		used[key] = true
+
+		fv, ok := fieldByIndex(v, f.index, true)
+		if !ok {
+			continue
+		}
+		keyPath := joinPointer(path, key)
+		d.keys = append(d.keys, keyPath)
+		if err := d.decode(keyPath, m[key], fv); err != nil {
+			return err
+		}
+	}
+
+	for k := range m {
+		if !used[k] {
+			d.unused = append(d.unused, joinPointer(path, k))
+		}
+	}
+	return nil
+}
+
+// lookupFieldKey returns the key of the map matching the field name, preferring an
+// exact match over the first unused case-insensitive match in sorted order.
+func lookupFieldKey(m map[string]any, name string, used map[string]bool) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	found := ""
+	for k := range m {
+		if !used[k] && strings.EqualFold(k, name) && (found == "" || k < found) {
+			found = k
+		}
+	}
+	return found, found != ""
+}
+
+func (d *structDecoder) decode(path string, in any, out reflect.Value) error {
+	t := out.Type()
+	if in == nil {
+		out.Set(reflect.Zero(t))
+		return nil
+	}
+
+	iv := reflect.ValueOf(in)
+	switch {
+	case t.Kind() == reflect.Interface:
+		if !iv.Type().AssignableTo(t) {
+			return newConversionError(path, in, t, errIncompatibleType)
+		}
+		out.Set(reflect.ValueOf(deepCopyValue(in)))
+		return nil
+	case t.Kind() == reflect.Pointer:
+		if out.IsNil() {
+			out.Set(reflect.New(t.Elem()))
+		}
+		return d.decode(path, in, out.Elem())
+	case iv.Type() == t && t.Kind() != reflect.Map && t.Kind() != reflect.Slice:
+		out.Set(iv)
+		return nil
+	case iv.Kind() == reflect.String && reflect.PointerTo(t).Implements(textUnmarshalerType):
+		if err := out.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(iv.String())); err != nil {
+			return newConversionError(path, in, t, err)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := in.(map[string]any)
+		if !ok {
+			return newConversionError(path, in, t, errIncompatibleType)
+		}
+		return d.decodeStruct(path, m, out)
+	case reflect.Map:
+		return d.decodeMap(path, in, iv, out)
+	case reflect.Slice, reflect.Array:
+		return d.decodeSlice(path, in, iv, out)
+	default:
+		val, err := convertScalar(in, t, d.opts.WeaklyTyped)
+		if err != nil {
+			return newConversionError(path, in, t, err)
+		}
+		out.Set(val)
+		return nil
+	}
+}
+
+func (d *structDecoder) decodeMap(path string, in any, iv reflect.Value, out reflect.Value) error {
+	t := out.Type()
+	if iv.Kind() != reflect.Map {
+		return newConversionError(path, in, t, errIncompatibleType)
+	}
+	if out.IsNil() {
+		out.Set(reflect.MakeMapWithSize(t, iv.Len()))
+	}
+
+	keys := iv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessValues(keys[i].Interface(), keys[j].Interface())
+	})
+	for _, k := range keys {
+		keyPath := joinPointer(path, fmt.Sprint(k.Interface()))
+		key, err := convertScalar(k.Interface(), t.Key(), d.opts.WeaklyTyped)
+		if err != nil {
+			return newConversionError(keyPath, k.Interface(), t.Key(), err)
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decode(keyPath, iv.MapIndex(k).Interface(), elem); err != nil {
+			return err
+		}
+		out.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+func (d *structDecoder) decodeSlice(path string, in any, iv reflect.Value, out reflect.Value) error {
+	t := out.Type()
+	if iv.Kind() != reflect.Slice && iv.Kind() != reflect.Array {
+		return newConversionError(path, in, t, errIncompatibleType)
+	}
+
+	res := out
+	if t.Kind() == reflect.Slice {
+		res = reflect.MakeSlice(t, iv.Len(), iv.Len())
+	} else if iv.Len() > t.Len() {
+		return newConversionError(path, in, t, errOutOfRange)
+	}
+	for i := 0; i < iv.Len(); i++ {
+		if err := d.decode(joinPointer(path, strconv.Itoa(i)), iv.Index(i).Interface(), res.Index(i)); err != nil {
+			return err
+		}
+	}
+	for i := iv.Len(); t.Kind() == reflect.Array && i < t.Len(); i++ {
+		res.Index(i).Set(reflect.Zero(t.Elem()))
+	}
+	out.Set(res)
+	return nil
+}
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+type structFieldsKey struct {
+	t       reflect.Type
+	tagName string
+}
+
+var structFieldsCache sync.Map
+
+// cachedStructFields returns the fields of a struct type as resolved by structFields,
+// caching the result.
+func cachedStructFields(t reflect.Type, tagName string) []structField {
+	key := structFieldsKey{t: t, tagName: tagName}
+	if fields, ok := structFieldsCache.Load(key); ok {
+		return fields.([]structField)
+	}
+	fields, _ := structFieldsCache.LoadOrStore(key, structFields(t, tagName))
+	return fields.([]structField)
+}
+
+// structFields resolves the named fields of a struct type, including the fields of
+// inlined structs. Fields declared directly take precedence over inlined fields with
+// the same name, and shallower inlined fields over deeper ones.
+func structFields(t reflect.Type, tagName string) []structField {
+	return collectStructFields(t, tagName, make(map[reflect.Type]bool))
+}
+
+// collectStructFields resolves the fields of t like structFields. visiting holds the
+// struct types currently being inlined. Like encoding/json, a struct embedded
+// within itself, such as *T in type T struct{ *T; Name string }, is skipped rather
+// than inlined endlessly.
+func collectStructFields(t reflect.Type, tagName string, visiting map[reflect.Type]bool) []structField {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields, inlined []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, options, skip := parseFieldTag(f, tagName)
+		if skip {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		inline := ft.Kind() == reflect.Struct && (hasTagOption(options, "inline") || (f.Anonymous && name == ""))
+		if inline {
+			if visiting[ft] {
+				continue
+			}
+			for _, sub := range collectStructFields(ft, tagName, visiting) {
+				sub.index = append([]int{i}, sub.index...)
+				inlined = append(inlined, sub)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{name: name, index: []int{i}, omitEmpty: hasTagOption(options, "omitempty")})
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		seen[f.name] = true
+	}
+	for _, f := range inlined {
+		if !seen[f.name] {
+			seen[f.name] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// parseFieldTag returns the name and options of a field's tag, and whether the
+// field should be skipped.
+func parseFieldTag(f reflect.StructField, tagName string) (string, string, bool) {
+	var tag string
+	if tagName != "" {
+		tag = f.Tag.Get(tagName)
+	} else if t, ok := f.Tag.Lookup("map"); ok {
+		tag = t
+	} else {
+		tag = f.Tag.Get("json")
+	}
+	if tag == "-" {
+		return "", "", true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	return name, options, false
+}
+
+func hasTagOption(options string, option string) bool {
+	for options != "" {
+		var opt string
+		opt, options, _ = strings.Cut(options, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByIndex returns the nested field of the struct v, following embedded
+// pointers. If alloc is true nil embedded pointers are allocated, otherwise ok is
+// false when one is encountered.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
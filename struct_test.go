@@ -0,0 +1,364 @@
+package maps
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTLS struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key,omitempty"`
+}
+
+type testMeta struct {
+	Owner string `map:"owner"`
+}
+
+type testServer struct {
+	testMeta
+	Host     string            `map:"host" json:"hostname"`
+	Port     int               `json:"port"`
+	Timeout  time.Duration     `json:"timeout,omitempty"`
+	TLS      *testTLS          `json:"tls,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Limits   testLimits        `json:"limits,inline"`
+	Started  time.Time         `json:"started,omitempty"`
+	Extra    any               `json:"extra,omitempty"`
+	Password string            `json:"-"`
+	internal string
+}
+
+type testLimits struct {
+	MaxConns int `json:"maxConns"`
+}
+
+func TestFromStruct(t *testing.T) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := testServer{
+		testMeta: testMeta{Owner: "ops"},
+		Host:     "localhost",
+		Port:     8080,
+		TLS:      &testTLS{Cert: "cert.pem"},
+		Tags:     []string{"a", "b"},
+		Limits:   testLimits{MaxConns: 10},
+		Started:  started,
+		Password: "secret",
+		internal: "hidden",
+	}
+
+	m, err := FromStruct(&server, StructOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"owner":    "ops",
+		"host":     "localhost",
+		"port":     8080,
+		"tls":      map[string]any{"cert": "cert.pem"},
+		"tags":     []any{"a", "b"},
+		"maxConns": 10,
+		"started":  started,
+	}, m)
+
+	m, err = FromStruct(server, StructOptions{TagName: "json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", m["hostname"])
+	assert.Equal(t, "ops", m["Owner"])
+
+	_, err = FromStruct(map[string]any{}, StructOptions{})
+	assert.EqualError(t, err, "expected a struct, got map[string]interface {}")
+	_, err = FromStruct((*testServer)(nil), StructOptions{})
+	assert.EqualError(t, err, "expected a struct, got nil *maps.testServer")
+}
+
+type testRecursive struct {
+	*testRecursive
+	Name string
+}
+
+type MutualA struct {
+	*MutualB
+	A string
+}
+
+type MutualB struct {
+	*MutualA
+	B string
+}
+
+func TestStruct_RecursiveEmbedding(t *testing.T) {
+	m, err := FromStruct(testRecursive{testRecursive: &testRecursive{Name: "inner"}, Name: "outer"}, StructOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"Name": "outer"}, m)
+
+	var r testRecursive
+	assert.NoError(t, ToStruct(map[string]any{"Name": "x"}, &r, StructOptions{}))
+	assert.Equal(t, testRecursive{Name: "x"}, r)
+
+	m, err = FromStruct(MutualA{MutualB: &MutualB{B: "b"}, A: "a"}, StructOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"A": "a", "B": "b"}, m)
+
+	var a MutualA
+	assert.NoError(t, ToStruct(map[string]any{"A": "a", "B": "b"}, &a, StructOptions{}))
+	assert.Equal(t, MutualA{MutualB: &MutualB{B: "b"}, A: "a"}, a)
+}
+
+func TestToStruct(t *testing.T) {
+	doc := decodeJSON[map[string]any](t, `{
+		"owner": "ops",
+		"host": "0.0.0.0",
+		"PORT": 9090,
+		"tls": {"cert": "cert.pem", "key": "key.pem"},
+		"tags": ["x"],
+		"labels": {"env": "prod"},
+		"maxConns": 100,
+		"started": "2024-01-02T03:04:05Z",
+		"extra": {"nested": [1]}
+	}`)
+
+	server := testServer{Timeout: 30 * time.Second, Password: "keep"}
+	err := ToStruct(doc, &server, StructOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, testServer{
+		testMeta: testMeta{Owner: "ops"},
+		Host:     "0.0.0.0",
+		Port:     9090,
+		Timeout:  30 * time.Second,
+		TLS:      &testTLS{Cert: "cert.pem", Key: "key.pem"},
+		Tags:     []string{"x"},
+		Labels:   map[string]string{"env": "prod"},
+		Limits:   testLimits{MaxConns: 100},
+		Started:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Extra:    map[string]any{"nested": []any{float64(1)}},
+		Password: "keep",
+	}, server)
+
+	// The decoded value doesn't share memory with the map.
+	doc["extra"].(map[string]any)["nested"] = nil
+	assert.Equal(t, map[string]any{"nested": []any{float64(1)}}, server.Extra)
+}
+
+func TestToStruct_RoundTrip(t *testing.T) {
+	server := testServer{
+		testMeta: testMeta{Owner: "ops"},
+		Host:     "localhost",
+		Port:     8080,
+		Timeout:  time.Minute,
+		TLS:      &testTLS{Cert: "cert.pem"},
+		Tags:     []string{"a"},
+		Labels:   map[string]string{"env": "dev"},
+		Limits:   testLimits{MaxConns: 5},
+	}
+	m, err := FromStruct(server, StructOptions{})
+	assert.NoError(t, err)
+
+	var decoded testServer
+	assert.NoError(t, ToStruct(m, &decoded, StructOptions{}))
+	assert.Equal(t, server, decoded)
+}
+
+func TestToStruct_MergeConfigs(t *testing.T) {
+	type config struct {
+		Host    string `json:"host,omitempty"`
+		Port    int    `json:"port,omitempty"`
+		Verbose bool   `json:"verbose,omitempty"`
+	}
+
+	defaults, err := FromStruct(config{Host: "localhost", Port: 8080}, StructOptions{})
+	assert.NoError(t, err)
+	overrides, err := FromStruct(config{Port: 9090, Verbose: true}, StructOptions{})
+	assert.NoError(t, err)
+
+	var merged config
+	err = ToStruct(Merge(OverwriteResolver[any](), defaults, overrides), &merged, StructOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Host: "localhost", Port: 9090, Verbose: true}, merged)
+}
+
+func TestToStruct_WeaklyTyped(t *testing.T) {
+	type config struct {
+		Port    int           `json:"port"`
+		Ratio   float32       `json:"ratio"`
+		Debug   bool          `json:"debug"`
+		Timeout time.Duration `json:"timeout"`
+		Name    string        `json:"name"`
+		Workers uint8         `json:"workers"`
+		Ports   []int         `json:"ports"`
+		Weights map[int]bool  `json:"weights"`
+	}
+
+	env := map[string]any{
+		"port":    "8080",
+		"ratio":   "0.5",
+		"debug":   "true",
+		"timeout": "1m30s",
+		"name":    42,
+		"workers": "0x10",
+		"ports":   []any{"80", 443},
+		"weights": map[string]any{"1": "1", "2": 0},
+	}
+
+	var cfg config
+	assert.NoError(t, ToStruct(env, &cfg, StructOptions{WeaklyTyped: true}))
+	assert.Equal(t, config{
+		Port:    8080,
+		Ratio:   0.5,
+		Debug:   true,
+		Timeout: 90 * time.Second,
+		Name:    "42",
+		Workers: 16,
+		Ports:   []int{80, 443},
+		Weights: map[int]bool{1: true, 2: false},
+	}, cfg)
+
+	err := ToStruct(env, &config{}, StructOptions{})
+	assert.EqualError(t, err, `key "/port": cannot convert "8080" (string) to int`)
+}
+
+func TestToStruct_ConversionErrors(t *testing.T) {
+	type config struct {
+		Port    int           `json:"port"`
+		Small   int8          `json:"small"`
+		Count   uint          `json:"count"`
+		Timeout time.Duration `json:"timeout"`
+		Servers []struct {
+			Port int `json:"port"`
+		} `json:"servers"`
+		Started time.Time `json:"started"`
+		Pair    [1]int    `json:"pair"`
+	}
+
+	tests := []struct {
+		name string
+		doc  map[string]any
+		weak bool
+		err  string
+	}{
+		{
+			name: "Fraction To Int",
+			doc:  map[string]any{"port": 80.5},
+			err:  `key "/port": cannot convert 80.5 (float64) to int: not an integer`,
+		},
+		{
+			name: "Overflow",
+			doc:  map[string]any{"small": 300},
+			err:  `key "/small": cannot convert 300 (int) to int8: value out of range`,
+		},
+		{
+			name: "Negative To Unsigned",
+			doc:  map[string]any{"count": -1},
+			err:  `key "/count": cannot convert -1 (int) to uint: value out of range`,
+		},
+		{
+			name: "Invalid Weak Number",
+			doc:  map[string]any{"port": "http"},
+			weak: true,
+			err:  `key "/port": cannot convert "http" (string) to int: invalid syntax`,
+		},
+		{
+			name: "Invalid Duration",
+			doc:  map[string]any{"timeout": "soon"},
+			weak: true,
+			err:  `key "/timeout": cannot convert "soon" (string) to time.Duration: time: invalid duration "soon"`,
+		},
+		{
+			name: "Nested Path",
+			doc:  map[string]any{"servers": []any{map[string]any{"port": 1}, map[string]any{"port": true}}},
+			err:  `key "/servers/1/port": cannot convert true (bool) to int`,
+		},
+		{
+			name: "Map To Scalar",
+			doc:  map[string]any{"port": map[string]any{}},
+			err:  `key "/port": cannot convert map[] (map[string]interface {}) to int`,
+		},
+		{
+			name: "Text Unmarshaler",
+			doc:  map[string]any{"started": "yesterday"},
+			err:  `key "/started": cannot convert "yesterday" (string) to time.Time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+		{
+			name: "Array Too Long",
+			doc:  map[string]any{"pair": []any{1, 2}},
+			err:  `key "/pair": cannot convert [1 2] ([]interface {}) to [1]int: value out of range`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cfg config
+			err := ToStruct(test.doc, &cfg, StructOptions{WeaklyTyped: test.weak})
+			assert.EqualError(t, err, test.err)
+
+			var convErr *ConversionError
+			assert.True(t, errors.As(err, &convErr))
+		})
+	}
+
+	assert.EqualError(t, ToStruct(map[string]any{}, testServer{}, StructOptions{}),
+		"target must be a non-nil pointer to a struct, got maps.testServer")
+}
+
+func TestToStruct_UnusedAndUnset(t *testing.T) {
+	doc := map[string]any{
+		"host":  "localhost",
+		"port":  80,
+		"debug": true,
+		"tls":   map[string]any{"cert": "cert.pem", "ca": "ca.pem"},
+	}
+
+	var meta StructMetadata
+	var server testServer
+	err := ToStruct(doc, &server, StructOptions{Metadata: &meta})
+	assert.NoError(t, err)
+	assert.Equal(t, StructMetadata{
+		Keys:   []string{"/host", "/port", "/tls", "/tls/cert"},
+		Unused: []string{"/debug", "/tls/ca"},
+		Unset: []string{
+			"/extra", "/labels", "/maxConns", "/owner", "/started", "/tags", "/timeout", "/tls/key",
+		},
+	}, meta)
+
+	err = ToStruct(doc, &server, StructOptions{ErrorUnused: true})
+	assert.Equal(t, &StructKeysError{Unused: []string{"/debug", "/tls/ca"}}, err)
+	assert.EqualError(t, err, "unused keys: /debug, /tls/ca")
+
+	err = ToStruct(map[string]any{"cert": "c"}, &testTLS{}, StructOptions{ErrorUnused: true, ErrorUnset: true})
+	assert.EqualError(t, err, "unset fields: /key")
+}
+
+func TestConvertScalar(t *testing.T) {
+	tests := []struct {
+		in       any
+		typ      reflect.Type
+		weak     bool
+		expected any
+		err      error
+	}{
+		{in: 42, typ: reflect.TypeOf(int64(0)), expected: int64(42)},
+		{in: float64(42), typ: reflect.TypeOf(0), expected: 42},
+		{in: uint64(7), typ: reflect.TypeOf(float32(0)), expected: float32(7)},
+		{in: "1e3", typ: reflect.TypeOf(0), weak: true, expected: 1000},
+		{in: true, typ: reflect.TypeOf(0), weak: true, expected: 1},
+		{in: 0, typ: reflect.TypeOf(false), weak: true, expected: false},
+		{in: 1.5, typ: reflect.TypeOf(""), weak: true, expected: "1.5"},
+		{in: int64(time.Second), typ: durationType, expected: time.Second},
+		{in: "1", typ: reflect.TypeOf(0), err: errIncompatibleType},
+		{in: 1, typ: reflect.TypeOf(""), err: errIncompatibleType},
+		{in: 1e20, typ: reflect.TypeOf(0), err: errOutOfRange},
+		{in: []any{}, typ: reflect.TypeOf(0), weak: true, err: errIncompatibleType},
+	}
+
+	for _, test := range tests {
+		val, err := convertScalar(test.in, test.typ, test.weak)
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, val.Interface())
+	}
+}
@@ -0,0 +1,196 @@
+package maps
+
+import (
+	"sort"
+)
+
+// ThreeWayConflict describes a key that was changed differently in ours and theirs
+// relative to base. The In fields report whether the key exists in the respective
+// map, a change can be a modification, an addition or a removal.
+type ThreeWayConflict[K comparable, V any] struct {
+	Key      K
+	Base     V
+	InBase   bool
+	Ours     V
+	InOurs   bool
+	Theirs   V
+	InTheirs bool
+}
+
+// ThreeWayResolver is a function type that is invoked when a three-way merge finds
+// a key changed differently on both sides. It returns the resolved value, whether
+// the key should be present in the merged map, and ok set to false if the conflict
+// should be left unresolved and reported instead.
+type ThreeWayResolver[K comparable, V any] func(conflict ThreeWayConflict[K, V]) (val V, present bool, ok bool)
+
+// OursResolver returns a ThreeWayResolver that resolves every conflict in favour of
+// ours.
+func OursResolver[K comparable, V any]() ThreeWayResolver[K, V] {
+	return func(c ThreeWayConflict[K, V]) (V, bool, bool) {
+		return c.Ours, c.InOurs, true
+	}
+}
+
+// TheirsResolver returns a ThreeWayResolver that resolves every conflict in favour
+// of theirs.
+func TheirsResolver[K comparable, V any]() ThreeWayResolver[K, V] {
+	return func(c ThreeWayConflict[K, V]) (V, bool, bool) {
+		return c.Theirs, c.InTheirs, true
+	}
+}
+
+// ThreeWayMerge merges the changes made in ours and theirs relative to their common
+// ancestor base into a new map. A key changed on only one side takes that side's
+// change, including removals, while a key changed identically on both sides takes
+// the shared change. A key changed differently on both sides is a conflict that is
+// passed to fn, if provided. Conflicts fn doesn't resolve, or all conflicts if fn
+// is nil, keep the value from ours and are returned sorted by key.
+func ThreeWayMerge[M ~map[K]V, K, V comparable](base, ours, theirs M, fn ThreeWayResolver[K, V]) (map[K]V, []ThreeWayConflict[K, V]) {
+	merged := make(map[K]V, len(ours))
+	var conflicts []ThreeWayConflict[K, V]
+
+	visit := func(k K) {
+		b, inBase := base[k]
+		o, inOurs := ours[k]
+		t, inTheirs := theirs[k]
+
+		oursChanged := inOurs != inBase || (inOurs && o != b)
+		theirsChanged := inTheirs != inBase || (inTheirs && t != b)
+		sameChange := inOurs == inTheirs && (!inOurs || o == t)
+
+		if theirsChanged && (!oursChanged || sameChange) {
+			if inTheirs {
+				merged[k] = t
+			}
+			return
+		}
+		if !theirsChanged || sameChange {
+			if inOurs {
+				merged[k] = o
+			}
+			return
+		}
+
+		conflict := ThreeWayConflict[K, V]{
+			Key:  k,
+			Base: b, InBase: inBase,
+			Ours: o, InOurs: inOurs,
+			Theirs: t, InTheirs: inTheirs,
+		}
+		if fn != nil {
+			if val, present, ok := fn(conflict); ok {
+				if present {
+					merged[k] = val
+				}
+				return
+			}
+		}
+		conflicts = append(conflicts, conflict)
+		if inOurs {
+			merged[k] = o
+		}
+	}
+
+	for k := range base {
+		visit(k)
+	}
+	for k := range ours {
+		if _, ok := base[k]; !ok {
+			visit(k)
+		}
+	}
+	for k := range theirs {
+		_, inBase := base[k]
+		_, inOurs := ours[k]
+		if !inBase && !inOurs {
+			visit(k)
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return lessValues(conflicts[i].Key, conflicts[j].Key)
+	})
+	return merged, conflicts
+}
+
+// DeepThreeWayMerge is the nested variant of ThreeWayMerge for documents such as
+// decoded JSON or YAML. When both sides changed a nested map differently the maps
+// are merged recursively, so only the individual values changed on both sides
+// conflict. The Key of each conflict is the JSON Pointer of the conflicting value
+// and conflicts are ordered by path. Values are compared using JSON semantics.
+//
+// The inputs are not modified and the result doesn't share memory with them.
+func DeepThreeWayMerge(base, ours, theirs map[string]any, fn ThreeWayResolver[string, any]) (map[string]any, []ThreeWayConflict[string, any]) {
+	var conflicts []ThreeWayConflict[string, any]
+	merged := deepThreeWayMerge("", base, ours, theirs, fn, &conflicts)
+	return merged, conflicts
+}
+
+func deepThreeWayMerge(path string, base, ours, theirs map[string]any, fn ThreeWayResolver[string, any],
+	conflicts *[]ThreeWayConflict[string, any]) map[string]any {
+
+	keys := make([]string, 0, len(ours))
+	seen := make(map[string]struct{}, len(ours))
+	for _, m := range []map[string]any{base, ours, theirs} {
+		for k := range m {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	merged := make(map[string]any, len(keys))
+	for _, k := range keys {
+		b, inBase := base[k]
+		o, inOurs := ours[k]
+		t, inTheirs := theirs[k]
+
+		oursChanged := inOurs != inBase || (inOurs && !jsonEqual(o, b))
+		theirsChanged := inTheirs != inBase || (inTheirs && !jsonEqual(t, b))
+		sameChange := inOurs == inTheirs && (!inOurs || jsonEqual(o, t))
+
+		if theirsChanged && (!oursChanged || sameChange) {
+			if inTheirs {
+				merged[k] = deepCopyValue(t)
+			}
+			continue
+		}
+		if !theirsChanged || sameChange {
+			if inOurs {
+				merged[k] = deepCopyValue(o)
+			}
+			continue
+		}
+
+		childPath := joinPointer(path, k)
+		oursMap, oursIsMap := o.(map[string]any)
+		theirsMap, theirsIsMap := t.(map[string]any)
+		baseMap, baseIsMap := b.(map[string]any)
+		if oursIsMap && theirsIsMap && (baseIsMap || !inBase) {
+			merged[k] = deepThreeWayMerge(childPath, baseMap, oursMap, theirsMap, fn, conflicts)
+			continue
+		}
+
+		conflict := ThreeWayConflict[string, any]{
+			Key:  childPath,
+			Base: b, InBase: inBase,
+			Ours: o, InOurs: inOurs,
+			Theirs: t, InTheirs: inTheirs,
+		}
+		if fn != nil {
+			if val, present, ok := fn(conflict); ok {
+				if present {
+					merged[k] = deepCopyValue(val)
+				}
+				continue
+			}
+		}
+		*conflicts = append(*conflicts, conflict)
+		if inOurs {
+			merged[k] = deepCopyValue(o)
+		}
+	}
+	return merged
+}
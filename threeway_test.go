@@ -0,0 +1,170 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreeWayMerge(t *testing.T) {
+	base := map[string]string{
+		"theme":    "light",
+		"language": "en",
+		"timezone": "UTC",
+		"font":     "mono",
+		"layout":   "grid",
+		"sidebar":  "left",
+	}
+	ours := map[string]string{
+		"theme":    "dark",  // changed by us
+		"language": "en",    // unchanged
+		"timezone": "PST",   // changed by both, same value
+		"font":     "serif", // changed by both, conflict
+		// layout removed by us, changed by them: conflict
+		"sidebar": "left", // removed by them
+		"density": "compact",
+	}
+	theirs := map[string]string{
+		"theme":    "light",
+		"language": "fr", // changed by them
+		"timezone": "PST",
+		"font":     "sans",
+		"layout":   "list",
+		"zoom":     "110%",
+	}
+
+	tests := []struct {
+		name      string
+		resolver  ThreeWayResolver[string, string]
+		expected  map[string]string
+		conflicts []string
+	}{
+		{
+			name: "Unresolved Conflicts Keep Ours",
+			expected: map[string]string{
+				"theme":    "dark",
+				"language": "fr",
+				"timezone": "PST",
+				"font":     "serif",
+				"density":  "compact",
+				"zoom":     "110%",
+			},
+			conflicts: []string{"font", "layout"},
+		},
+		{
+			name:     "Theirs Resolver",
+			resolver: TheirsResolver[string, string](),
+			expected: map[string]string{
+				"theme":    "dark",
+				"language": "fr",
+				"timezone": "PST",
+				"font":     "sans",
+				"layout":   "list",
+				"density":  "compact",
+				"zoom":     "110%",
+			},
+		},
+		{
+			name: "Partial Resolver",
+			resolver: func(c ThreeWayConflict[string, string]) (string, bool, bool) {
+				if c.Key == "font" {
+					return c.Ours + "," + c.Theirs, true, true
+				}
+				return "", false, false
+			},
+			expected: map[string]string{
+				"theme":    "dark",
+				"language": "fr",
+				"timezone": "PST",
+				"font":     "serif,sans",
+				"density":  "compact",
+				"zoom":     "110%",
+			},
+			conflicts: []string{"layout"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := ThreeWayMerge(base, ours, theirs, test.resolver)
+			assert.Equal(t, test.expected, merged)
+
+			var keys []string
+			for _, c := range conflicts {
+				keys = append(keys, c.Key)
+			}
+			assert.Equal(t, test.conflicts, keys)
+		})
+	}
+}
+
+func TestThreeWayMerge_ConflictDetails(t *testing.T) {
+	_, conflicts := ThreeWayMerge(
+		map[string]int{"a": 1},
+		map[string]int{},
+		map[string]int{"a": 2, "b": 3},
+		nil,
+	)
+	assert.Equal(t, []ThreeWayConflict[string, int]{
+		{Key: "a", Base: 1, InBase: true, Ours: 0, InOurs: false, Theirs: 2, InTheirs: true},
+	}, conflicts)
+
+	_, conflicts = ThreeWayMerge(
+		map[string]int{},
+		map[string]int{"a": 1},
+		map[string]int{"a": 2},
+		nil,
+	)
+	assert.Equal(t, []ThreeWayConflict[string, int]{
+		{Key: "a", Ours: 1, InOurs: true, Theirs: 2, InTheirs: true},
+	}, conflicts)
+}
+
+func TestDeepThreeWayMerge(t *testing.T) {
+	base := map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 8080, "tls": false},
+		"tags":   []any{"a"},
+		"name":   "api",
+	}
+	ours := map[string]any{
+		"server": map[string]any{"host": "0.0.0.0", "port": 9090, "tls": false},
+		"tags":   []any{"a", "b"},
+		"name":   "api",
+	}
+	theirs := map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 9091, "tls": true},
+		"tags":   []any{"a", "c"},
+		"name":   "web",
+		"owner":  map[string]any{"team": "x"},
+	}
+
+	merged, conflicts := DeepThreeWayMerge(base, ours, theirs, nil)
+	assert.Equal(t, map[string]any{
+		"server": map[string]any{"host": "0.0.0.0", "port": 9090, "tls": true},
+		"tags":   []any{"a", "b"},
+		"name":   "web",
+		"owner":  map[string]any{"team": "x"},
+	}, merged)
+	assert.Equal(t, []ThreeWayConflict[string, any]{
+		{Key: "/server/port", Base: 8080, InBase: true, Ours: 9090, InOurs: true, Theirs: 9091, InTheirs: true},
+		{Key: "/tags", Base: []any{"a"}, InBase: true, Ours: []any{"a", "b"}, InOurs: true, Theirs: []any{"a", "c"}, InTheirs: true},
+	}, conflicts)
+
+	merged, conflicts = DeepThreeWayMerge(base, ours, theirs, TheirsResolver[string, any]())
+	assert.Empty(t, conflicts)
+	assert.Equal(t, 9091, merged["server"].(map[string]any)["port"])
+	assert.Equal(t, []any{"a", "c"}, merged["tags"])
+}
+
+func TestDeepThreeWayMerge_NestedAdditions(t *testing.T) {
+	merged, conflicts := DeepThreeWayMerge(
+		map[string]any{},
+		map[string]any{"labels": map[string]any{"env": "prod", "team": "a"}},
+		map[string]any{"labels": map[string]any{"env": "prod", "team": "b", "tier": "web"}},
+		nil,
+	)
+	assert.Equal(t, map[string]any{"labels": map[string]any{"env": "prod", "team": "a", "tier": "web"}}, merged)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, "/labels/team", conflicts[0].Key)
+	assert.False(t, conflicts[0].InBase)
+}